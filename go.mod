@@ -5,6 +5,7 @@ go 1.24.1
 require (
 	github.com/google/go-github/v67 v67.0.0
 	github.com/spf13/cobra v1.9.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,245 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chart draws simple line charts of values over a run of labels,
+// usually days, as text for the terminal or as SVG.
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Series is a line on a chart. Values holds one value per label of the chart;
+// NaN marks labels without a value, such as days still to come.
+type Series struct {
+	Name   string
+	Values []float64
+	// Marker draws the series in text charts.
+	Marker rune
+	// Color draws the series in SVG charts, e.g. "#0969da".
+	Color string
+	// Dashed draws the series as a dashed line in SVG charts.
+	Dashed bool
+}
+
+// Chart is a set of series over the same labels. Series are drawn in order,
+// so later ones are drawn over earlier ones.
+type Chart struct {
+	Title  string
+	Labels []string
+	Series []Series
+}
+
+// top returns the largest value on the chart, or 1 when there is none, so
+// that the chart always has some height.
+func (c *Chart) top() float64 {
+	top := 0.0
+	for _, series := range c.Series {
+		for _, v := range series.Values {
+			if !math.IsNaN(v) && v > top {
+				top = v
+			}
+		}
+	}
+	if top == 0 {
+		return 1
+	}
+	return top
+}
+
+// textColumn is the width of one label's column in text charts.
+const textColumn = 3
+
+// Text writes the chart to w as text, height rows tall, with a legend.
+func (c *Chart) Text(w io.Writer, height int) error {
+	if height < 2 {
+		height = 2
+	}
+	top := c.top()
+	columns := len(c.Labels)
+
+	grid := make([][]rune, height)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", columns))
+	}
+	for _, series := range c.Series {
+		for i, v := range series.Values {
+			if i >= columns || math.IsNaN(v) {
+				continue
+			}
+			row := int(math.Round(v / top * float64(height-1)))
+			if row < 0 {
+				row = 0
+			}
+			if row >= height {
+				row = height - 1
+			}
+			grid[row][i] = series.Marker
+		}
+	}
+
+	// The axis is labelled at the top, middle and bottom
+	middle := (height - 1) / 2
+	axis := map[int]string{
+		0:          "0",
+		middle:     formatValue(top * float64(middle) / float64(height-1)),
+		height - 1: formatValue(top),
+	}
+	width := 0
+	for _, label := range axis {
+		if len(label) > width {
+			width = len(label)
+		}
+	}
+
+	var b strings.Builder
+	if c.Title != "" {
+		b.WriteString(c.Title + "\n\n")
+	}
+	for row := height - 1; row >= 0; row-- {
+		fmt.Fprintf(&b, "%*s │", width, axis[row])
+		for _, marker := range grid[row] {
+			b.WriteString(" " + string(marker) + " ")
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%*s └%s\n", width, "", strings.Repeat("─", columns*textColumn))
+
+	// Labels start under their marker and are left out where they would
+	// overlap the one before; the last one may run past the axis.
+	labels := []rune(strings.Repeat(" ", columns*textColumn+8))
+	free := 0
+	for i, label := range c.Labels {
+		runes := []rune(label)
+		start := i * textColumn
+		if start < free || start+len(runes) > len(labels) {
+			continue
+		}
+		copy(labels[start:], runes)
+		free = start + len(runes) + 1
+	}
+	fmt.Fprintf(&b, "%*s   %s\n", width, "", strings.TrimRight(string(labels), " "))
+
+	var legend []string
+	for _, series := range c.Series {
+		legend = append(legend, string(series.Marker)+" "+series.Name)
+	}
+	fmt.Fprintf(&b, "\n%*s   %s\n", width, "", strings.Join(legend, "   "))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// SVG dimensions and margins, in pixels.
+const (
+	svgWidth  = 720
+	svgHeight = 360
+	svgLeft   = 56
+	svgRight  = 24
+	svgTop    = 48
+	svgBottom = 64
+)
+
+// SVG writes the chart to w as a standalone SVG image.
+func (c *Chart) SVG(w io.Writer) error {
+	top := c.top()
+	plotWidth := float64(svgWidth - svgLeft - svgRight)
+	plotHeight := float64(svgHeight - svgTop - svgBottom)
+
+	x := func(i int) float64 {
+		if len(c.Labels) < 2 {
+			return svgLeft + plotWidth/2
+		}
+		return svgLeft + float64(i)*plotWidth/float64(len(c.Labels)-1)
+	}
+	y := func(v float64) float64 {
+		return svgTop + plotHeight - v/top*plotHeight
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		svgWidth, svgHeight, svgWidth, svgHeight)
+	b.WriteString(`<rect width="100%" height="100%" fill="white"/>` + "\n")
+	if c.Title != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="28" text-anchor="middle" font-size="16">%s</text>`+"\n", svgWidth/2, html.EscapeString(c.Title))
+	}
+
+	// Horizontal grid lines at zero, half and the top value
+	for _, v := range []float64{0, top / 2, top} {
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#d0d7de"/>`+"\n", svgLeft, y(v), svgWidth-svgRight, y(v))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n", svgLeft-8, y(v), formatValue(v))
+	}
+
+	// About ten labels fit along the bottom
+	step := (len(c.Labels) + 9) / 10
+	if step < 1 {
+		step = 1
+	}
+	for i := 0; i < len(c.Labels); i += step {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", x(i), svgHeight-svgBottom+20, html.EscapeString(c.Labels[i]))
+	}
+
+	for _, series := range c.Series {
+		color := series.Color
+		if color == "" {
+			color = "black"
+		}
+		dash := ""
+		if series.Dashed {
+			dash = ` stroke-dasharray="6 4"`
+		}
+
+		// Lines break where a value is missing
+		var points []string
+		flush := func() {
+			if len(points) > 0 {
+				fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2"%s points="%s"/>`+"\n", color, dash, strings.Join(points, " "))
+			}
+			points = nil
+		}
+		for i, v := range series.Values {
+			if i >= len(c.Labels) || math.IsNaN(v) {
+				flush()
+				continue
+			}
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), y(v)))
+		}
+		flush()
+	}
+
+	legendX := svgLeft
+	for _, series := range c.Series {
+		color := series.Color
+		if color == "" {
+			color = "black"
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`+"\n", legendX, svgHeight-22, color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", legendX+18, svgHeight-12, html.EscapeString(series.Name))
+		legendX += 30 + 8*len(series.Name)
+	}
+
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatValue formats an axis value with at most one decimal place.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chart
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func testChart() *Chart {
+	return &Chart{
+		Title:  "Sprint <1>",
+		Labels: []string{"01", "02", "03", "04"},
+		Series: []Series{
+			{Name: "Ideal", Values: []float64{4, 3, 2, 1}, Marker: '·', Color: "#8c959f", Dashed: true},
+			{Name: "Remaining", Values: []float64{4, 2, 2, math.NaN()}, Marker: '●', Color: "#0969da"},
+		},
+	}
+}
+
+func TestText(t *testing.T) {
+	var b strings.Builder
+	if err := testChart().Text(&b, 5); err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+
+	want := `Sprint <1>
+
+4 │ ●          
+  │    ·       
+2 │    ●  ●    
+  │          · 
+0 │            
+  └────────────
+    01 02 03 04
+
+    · Ideal   ● Remaining
+`
+	if b.String() != want {
+		t.Errorf("Text =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	var b strings.Builder
+	if err := testChart().SVG(&b); err != nil {
+		t.Fatalf("SVG: %v", err)
+	}
+	svg := b.String()
+
+	for _, want := range []string{
+		`<text x="360" y="28" text-anchor="middle" font-size="16">Sprint &lt;1&gt;</text>`,
+		// The missing last value ends the line early
+		`<polyline fill="none" stroke="#0969da" stroke-width="2" points="56.0,48.0 269.3,172.0 482.7,172.0"/>`,
+		`stroke-dasharray="6 4"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG is missing %s:\n%s", want, svg)
+		}
+	}
+	if !strings.HasSuffix(svg, "</svg>\n") {
+		t.Error("SVG is not closed")
+	}
+}
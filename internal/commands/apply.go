@@ -0,0 +1,214 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kriscoleman/gh-projects/internal/config"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/plan"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// applyOptions holds the flags specific to iteration apply.
+type applyOptions struct {
+	*BaseCommand
+	BatchSize int
+	Journal   string
+}
+
+func NewIterationApplyCmd(cfg *config.Config) *cobra.Command {
+	opts := &applyOptions{BaseCommand: &BaseCommand{config: cfg}}
+
+	cmd := &cobra.Command{
+		Use:   "apply <plan>",
+		Short: "Move the items in a rollover plan",
+		Long: `Move the items in a plan written by "iteration rollover --plan-out" to the
+plan's target iteration.
+
+Every item is checked against the plan first. Items that are no longer in the
+iteration they were planned from, or that were changed in any way since the
+plan was made, are refused and left alone; apply moves the rest and then
+fails, so that the drift gets noticed. Use --dry-run to check a plan without
+moving anything.
+
+Apply records the items it moves in a journal, like rollover does.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(cmd); err != nil {
+				return err
+			}
+			ctx, cancel := opts.Context(cmd.Context())
+			defer cancel()
+			return runIterationApply(ctx, opts, args[0])
+		},
+	}
+
+	opts.AddCommonFlags(cmd)
+	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", projects.DefaultBatchSize, fmt.Sprintf("Number of items to move per GraphQL request (1 to %d; 1 disables batching)", projects.MaxBatchSize))
+	cmd.Flags().StringVar(&opts.Journal, "journal", "", "Write the journal to `file` instead of the journal directory")
+
+	return cmd
+}
+
+func runIterationApply(ctx context.Context, opts *applyOptions, path string) error {
+	base := opts.BaseCommand
+	base.RouteOutput()
+
+	ui.Println("🚀 GitHub Projects - Apply Rollover Plan")
+	ui.Println("========================================")
+
+	planned, err := plan.Load(path)
+	if err != nil {
+		return err
+	}
+	if base.ProjectURL != "" && !planned.IsFor(base.ProjectURL) {
+		return fmt.Errorf("plan %s is for project %s, not %s", path, planned.Project.URL, base.ProjectURL)
+	}
+	base.ProjectURL = planned.Project.URL
+
+	ui.Printf("📝 Plan: %s\n", path)
+	ui.Printf("📂 Project: %s\n", planned.Project.URL)
+	ui.Printf("🕒 Planned %s\n", planned.CreatedAt.Local().Format("Jan 2 15:04"))
+
+	report := &changeReport{
+		Plan:    path,
+		Project: planned.Project.URL,
+		Field:   planned.Field.Name,
+		DryRun:  base.DryRun,
+		Items:   []*changeItem{},
+	}
+
+	if len(planned.Items) == 0 {
+		ui.Println("\n✅ The plan has no items to move.")
+		return base.WriteOutput(report)
+	}
+
+	client, err := base.GetGitHubClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize GitHub client: %w", err)
+	}
+
+	manager := base.NewManager(client, planned.Project.ID)
+
+	info, err := manager.GetIterations(ctx, planned.Field.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get iterations: %w", err)
+	}
+	report.Field = info.FieldName
+
+	to, err := info.Resolve(planned.To.ID)
+	if err != nil {
+		return fmt.Errorf("the plan's target iteration %s no longer exists: %w", planned.To.Title, err)
+	}
+	ui.Printf("🎯 To iteration: %s\n", to.Title)
+	if to.Completed {
+		ui.Printf("⚠️  Target iteration %s has been completed since the plan was made\n", to.Title)
+	}
+
+	itemIDs := make([]string, len(planned.Items))
+	for i, item := range planned.Items {
+		itemIDs[i] = item.ItemID
+	}
+	current, err := manager.GetItemIterations(ctx, info.FieldName, itemIDs)
+	if err != nil {
+		return err
+	}
+
+	var moves []journal.Entry
+	var items []*changeItem
+	refused := 0
+	for _, planItem := range planned.Items {
+		move := journal.Entry{
+			ItemID:          planItem.ItemID,
+			Ref:             planItem.Ref,
+			Title:           planItem.Title,
+			FromIterationID: planItem.FromIterationID,
+			FromIteration:   iterationName(info, planItem.FromIterationID, planItem.FromIteration),
+			ToIterationID:   to.ID,
+			ToIteration:     to.Title,
+		}
+		item := &changeItem{
+			ItemID:    planItem.ItemID,
+			Ref:       planItem.Ref,
+			Title:     planItem.Title,
+			Iteration: move.FromIteration,
+			Target:    to.Title,
+		}
+		report.Items = append(report.Items, item)
+
+		state, ok := current[planItem.ItemID]
+		switch {
+		case !ok:
+			item.Reason = "no longer in the project"
+		case state.IterationID != planItem.FromIterationID:
+			item.Reason = "moved to " + iterationName(info, state.IterationID, "") + " since the plan was made"
+		case state.UpdatedAt.After(planItem.UpdatedAt):
+			item.Reason = "changed at " + state.UpdatedAt.Local().Format("Jan 2 15:04") + ", after the plan was made"
+		default:
+			moves = append(moves, move)
+			items = append(items, item)
+			continue
+		}
+		item.Result = resultRefused
+		refused++
+		ui.Printf("🚫 Refusing %s: %s\n", describeEntry(move), item.Reason)
+	}
+
+	if len(moves) > 0 {
+		ui.Printf("\n📋 Items to move to %s (%d):\n", to.Title, len(moves))
+		ui.Println(strings.Repeat("-", 50))
+		for _, move := range moves {
+			ui.Printf("• %s (from %s)\n", describeEntry(move), move.FromIteration)
+		}
+	}
+
+	if base.DryRun {
+		for _, item := range items {
+			item.Result = resultDryRun
+		}
+		ui.Println("\n🔍 This was a dry run. No changes were made.")
+	} else if len(moves) > 0 {
+		ui.Printf("\n🔄 Moving items to %s...\n", to.Title)
+		changes := journal.New(journal.CommandApply,
+			journal.Project{URL: planned.Project.URL, ID: planned.Project.ID},
+			journal.Field{ID: info.FieldID, Name: info.FieldName},
+			viewerLogin(ctx, client))
+
+		progress, err := startJournal(changes, moves, opts.Journal)
+		if err != nil {
+			return err
+		}
+		counts := moveItems(ctx, manager, info.FieldID, moves, items, opts.BatchSize, resultMoved, progress)
+		progress.finish()
+		showMoveSummary(counts, "moved to "+to.Title, refused, "refused because they changed since the plan")
+	}
+
+	if err := base.WriteOutput(report); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("apply stopped before it finished: %w", context.Cause(ctx))
+	}
+	if refused > 0 {
+		return fmt.Errorf("%d of %d items in the plan changed after it was made and were not moved", refused, len(planned.Items))
+	}
+	return nil
+}
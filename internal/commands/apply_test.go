@@ -0,0 +1,122 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/plan"
+)
+
+// planRollover writes a plan for a silent rollover of Sprint 1 into Sprint 2
+// to a temporary file and returns its path.
+func planRollover(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", PlanOut: path, BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+	}}
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+	if got := movedItems(fake); len(got) != 0 {
+		t.Fatalf("planning moved %v, want nothing", got)
+	}
+	return path
+}
+
+// newApplyFake scripts the lookups apply makes for the plan above, with the
+// open issue untouched and the draft edited after the plan was made.
+func newApplyFake() *github.FakeTransport {
+	fake := newRolloverFake()
+	fake.Add(github.GetItemIterationsQuery, map[string]interface{}{"ids": []string{"PVTI_open", "PVTI_draft"}, "field": "Sprint"}, `{"data":{"nodes":[
+		{"id":"PVTI_open","updatedAt":"2026-10-01T09:00:00Z","fieldValueByName":{"iterationId":"it-1","title":"Sprint 1"}},
+		{"id":"PVTI_draft","updatedAt":"2026-10-05T16:00:00Z","fieldValueByName":{"iterationId":"it-1","title":"Sprint 1"}}
+	]}}`)
+	return fake
+}
+
+func TestIterationRolloverPlanOut(t *testing.T) {
+	p, err := plan.Load(planRollover(t))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if p.Project.ID != testProjectID || p.Field.ID != "F_iter" || p.To.ID != "it-2" || len(p.From) != 1 || p.From[0].ID != "it-1" {
+		t.Errorf("unexpected plan header: %+v", p)
+	}
+
+	var got []string
+	for _, item := range p.Items {
+		got = append(got, item.ItemID+"@"+item.UpdatedAt.Format("2006-01-02"))
+	}
+	want := []string{"PVTI_open@2026-10-01", "PVTI_draft@2026-10-02"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("planned items = %v, want %v", got, want)
+	}
+}
+
+func TestIterationApply(t *testing.T) {
+	path := planRollover(t)
+
+	fake := newApplyFake()
+	opts := &applyOptions{Journal: filepath.Join(t.TempDir(), "apply.json"), BaseCommand: &BaseCommand{
+		transport: fake,
+	}}
+	err := runIterationApply(context.Background(), opts, path)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 items") {
+		t.Fatalf("apply error = %v, want it to report the drifted item", err)
+	}
+
+	mutations := fake.CallsFor(github.UpdateItemIterationMutation)
+	if len(mutations) != 1 || mutations[0].Variables["itemId"] != "PVTI_open" || mutations[0].Variables["iterationId"] != "it-2" {
+		t.Fatalf("got mutations %v, want only PVTI_open moved to it-2", mutations)
+	}
+
+	recorded, err := journal.Load(opts.Journal)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if recorded.Command != journal.CommandApply || len(recorded.Entries) != 1 || recorded.Entries[0].FromIterationID != "it-1" {
+		t.Errorf("apply journal = %+v", recorded)
+	}
+}
+
+func TestIterationApplyDryRun(t *testing.T) {
+	path := planRollover(t)
+
+	fake := newApplyFake()
+	opts := &applyOptions{BaseCommand: &BaseCommand{
+		DryRun:    true,
+		transport: fake,
+	}}
+	if err := runIterationApply(context.Background(), opts, path); err == nil {
+		t.Error("dry run did not report the drifted item")
+	}
+
+	if got := movedItems(fake); len(got) != 0 {
+		t.Errorf("dry run moved %v, want nothing", got)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+)
+
+// defaultCommentTemplate is the comment --comment posts on moved items.
+const defaultCommentTemplate = "Carried over from {{.From}} to {{.To}}."
+
+// commentData is what a comment template can refer to.
+type commentData struct {
+	From    string
+	To      string
+	Ref     string
+	Title   string
+	Project string
+	// Carryovers is the item's carryover count after the move, or 0 when
+	// carryovers are not tracked.
+	Carryovers int
+}
+
+// auditTrail comments on and labels the issues and pull requests a rollover
+// moved, so that the people following them notice.
+type auditTrail struct {
+	// comment is nil when no comment is posted.
+	comment *template.Template
+	labels  []string
+}
+
+// newAuditTrail prepares the comment and labels requested by the rollover
+// flags. A comment template implies --comment.
+func newAuditTrail(opts *rolloverOptions) (*auditTrail, error) {
+	audit := &auditTrail{}
+	for _, label := range opts.Labels {
+		if label = strings.TrimSpace(label); label != "" {
+			audit.labels = append(audit.labels, label)
+		}
+	}
+
+	text := opts.CommentTemplate
+	if text == "" && opts.Comment {
+		text = defaultCommentTemplate
+	}
+
+	// Plans record only the moves, so apply would never add these
+	if opts.PlanOut != "" && (text != "" || len(audit.labels) > 0) {
+		return nil, fmt.Errorf("--comment, --comment-template and --label cannot be used with --plan-out: iteration apply does not comment on or label the items it moves")
+	}
+	if text != "" {
+		comment, err := template.New("comment").Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --comment-template: %w", err)
+		}
+		audit.comment = comment
+	}
+	return audit, nil
+}
+
+func (a *auditTrail) enabled() bool {
+	return a.comment != nil || len(a.labels) > 0
+}
+
+// describe summarizes what the audit trail adds, e.g. "a comment and the
+// carryover label".
+func (a *auditTrail) describe() string {
+	var parts []string
+	if a.comment != nil {
+		parts = append(parts, "a comment")
+	}
+	switch len(a.labels) {
+	case 0:
+	case 1:
+		parts = append(parts, fmt.Sprintf("the %s label", a.labels[0]))
+	default:
+		parts = append(parts, fmt.Sprintf("the labels %s", strings.Join(a.labels, ", ")))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// annotatable returns the issues that can be commented on and labeled; draft
+// issues live only in the project and are left out.
+func annotatable(issues []*github.Issue) []*github.Issue {
+	var result []*github.Issue
+	for _, issue := range issues {
+		if issue.Kind != github.KindDraftIssue && issue.Repository.Name != "" {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+// apply comments on and labels each of issues, which were moved to the
+// iteration to, and returns how many of them were left without. A failure
+// is reported but does not undo the move.
+func (a *auditTrail) apply(ctx context.Context, writer github.IssueWriter, issues []*github.Issue, to, projectURL string) int {
+	failed := 0
+	for i, issue := range issues {
+		if ctx.Err() != nil {
+			return failed + len(issues) - i
+		}
+		if err := a.annotate(ctx, writer, issue, to, projectURL); err != nil {
+			ui.Printf("⚠️  Warning: %s was moved, but %v\n", issue.Ref(), err)
+			failed++
+		}
+	}
+	return failed
+}
+
+func (a *auditTrail) annotate(ctx context.Context, writer github.IssueWriter, issue *github.Issue, to, projectURL string) error {
+	owner, repo := issue.Repository.Owner.Login, issue.Repository.Name
+
+	if a.comment != nil {
+		var body strings.Builder
+		err := a.comment.Execute(&body, commentData{
+			From:       issue.IterationTitle,
+			To:         to,
+			Ref:        issue.Ref(),
+			Title:      issue.Title,
+			Project:    projectURL,
+			Carryovers: issue.Carryovers,
+		})
+		if err != nil {
+			return fmt.Errorf("the comment template failed: %w", err)
+		}
+		if err := writer.AddComment(ctx, owner, repo, issue.Number, body.String()); err != nil {
+			return err
+		}
+	}
+
+	if len(a.labels) > 0 {
+		if err := writer.AddLabels(ctx, owner, repo, issue.Number, a.labels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
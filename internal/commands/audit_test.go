@@ -0,0 +1,128 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+)
+
+func TestIterationRolloverAuditTrail(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   rolloverOptions
+		dryRun bool
+		want   []github.FakeIssueCall
+	}{
+		{
+			name: "comment and label",
+			opts: rolloverOptions{Comment: true, Labels: []string{"carryover"}},
+			want: []github.FakeIssueCall{
+				{Owner: "acme", Repo: "web", Number: 1, Body: "Carried over from Sprint 1 to Sprint 2."},
+				{Owner: "acme", Repo: "web", Number: 1, Labels: []string{"carryover"}},
+			},
+		},
+		{
+			name: "comment template",
+			opts: rolloverOptions{CommentTemplate: "{{.Ref}} rolled into {{.To}}"},
+			want: []github.FakeIssueCall{
+				{Owner: "acme", Repo: "web", Number: 1, Body: "#1 rolled into Sprint 2"},
+			},
+		},
+		{
+			name:   "dry run",
+			opts:   rolloverOptions{Comment: true, Labels: []string{"carryover"}},
+			dryRun: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &github.FakeIssueWriter{}
+			opts := tt.opts
+			opts.From, opts.To = "previous", "current"
+			opts.BaseCommand = &BaseCommand{
+				ProjectURL: testProjectURL,
+				Silent:     true,
+				DryRun:     tt.dryRun,
+				transport:  newRolloverFake(),
+				issues:     writer,
+			}
+
+			if err := runIterationRollover(context.Background(), &opts); err != nil {
+				t.Fatalf("rollover: %v", err)
+			}
+
+			// The draft issue has no repository to comment in
+			if got := writer.Calls(); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("issue writes = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIterationRolloverAuditTrailFailure(t *testing.T) {
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", Labels: []string{"carryover"}, BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+		issues:     &github.FakeIssueWriter{Err: errors.New("HTTP 403")},
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover failed because of the label: %v", err)
+	}
+	if got := movedItems(fake); len(got) != 2 {
+		t.Errorf("moved %v, want both items moved despite the label failing", got)
+	}
+}
+
+func TestIterationRolloverInvalidCommentTemplate(t *testing.T) {
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", CommentTemplate: "{{.From", BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err == nil {
+		t.Fatal("rollover accepted an invalid comment template")
+	}
+	if got := len(fake.Calls()); got != 0 {
+		t.Errorf("sent %d requests before rejecting the template, want none", got)
+	}
+}
+
+func TestIterationRolloverAuditTrailWithPlanOut(t *testing.T) {
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", Comment: true, PlanOut: filepath.Join(t.TempDir(), "plan.json"), BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err == nil {
+		t.Fatal("rollover accepted --comment with --plan-out, which apply would drop")
+	}
+	if got := len(fake.Calls()); got != 0 {
+		t.Errorf("sent %d requests before rejecting the flags, want none", got)
+	}
+}
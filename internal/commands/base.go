@@ -15,11 +15,22 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
 	"strconv"
-	
-	"github.com/spf13/cobra"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/config"
 	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/output"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+	"github.com/spf13/cobra"
 )
 
 // BaseCommand provides common functionality for all commands
@@ -28,6 +39,39 @@ type BaseCommand struct {
 	DryRun     bool
 	Silent     bool
 	Token      string
+	Record     string
+	Replay     string
+	Profile    string
+	Output     output.Format
+	Timeout    time.Duration
+	Verbose    bool
+
+	IterationField string
+	Repositories   []string
+	IncludeTypes   []string
+
+	DoneFields   []string
+	DoneValues   []string
+	DonePattern  string
+	ClosedIsDone bool
+
+	// Completion is the resolved completion rule; nil means the default.
+	Completion *projects.CompletionRule
+	// Kinds are the resolved --include-types; nil means every kind.
+	Kinds []github.ItemKind
+
+	// config is the loaded configuration file, if any.
+	config *config.Config
+	// requireProject makes Complete fail when no project URL is resolved.
+	requireProject bool
+
+	// stdout receives structured output; nil means os.Stdout.
+	stdout io.Writer
+
+	// transport replaces the network transport when set, e.g. in tests.
+	transport github.Transport
+	// issues receives comments and labels when transport is set.
+	issues github.IssueWriter
 }
 
 // AddCommonFlags adds standard flags that many commands will need
@@ -36,26 +80,279 @@ func (b *BaseCommand) AddCommonFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&b.DryRun, "dry-run", false, "Preview changes without making them")
 	cmd.Flags().BoolVarP(&b.Silent, "silent", "s", false, "Run in silent mode (no prompts)")
 	cmd.Flags().StringVarP(&b.Token, "token", "t", "", "GitHub token for authentication (can also use GITHUB_TOKEN env var)")
+	cmd.Flags().StringVar(&b.Record, "record", "", "Record all GraphQL traffic as fixtures in `dir` (tokens are scrubbed)")
+	cmd.Flags().StringVar(&b.Replay, "replay", "", "Serve GraphQL responses from fixtures recorded in `dir` instead of GitHub")
+	cmd.Flags().StringVar(&b.Profile, "profile", "", "Configuration profile to use")
+	cmd.Flags().DurationVar(&b.Timeout, "timeout", 0, "Stop after this long, e.g. 10m (0 means no limit)")
+	cmd.MarkFlagsMutuallyExclusive("record", "replay")
+}
+
+// AddFieldFlag adds the flag selecting the iteration field to work with
+func (b *BaseCommand) AddFieldFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&b.IterationField, "field", "", "Iteration field to use, by name or ID (required when the project has several)")
+}
+
+// AddFilterFlags adds the flags that restrict which items a command acts on
+func (b *BaseCommand) AddFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&b.Repositories, "repo", nil, "Only act on items from these repositories (owner/name or name)")
+	cmd.Flags().StringSliceVar(&b.IncludeTypes, "include-types", []string{"issue", "pr", "draft"}, "Item types to act on: issue, pr, draft")
+}
+
+// AddCompletionFlags adds the flags that control when an item counts as done
+func (b *BaseCommand) AddCompletionFlags(cmd *cobra.Command) {
+	defaults := projects.DefaultCompletionRule()
+	cmd.Flags().StringSliceVar(&b.DoneFields, "done-field", defaults.Fields, "Single-select fields holding an item's status")
+	cmd.Flags().StringSliceVar(&b.DoneValues, "done-value", defaults.DoneValues, "Status values that mark an item as done")
+	cmd.Flags().StringVar(&b.DonePattern, "done-pattern", "", "Regular expression matching further status values that mark an item as done")
+	cmd.Flags().BoolVar(&b.ClosedIsDone, "closed-is-done", defaults.ClosedIsDone, "Treat closed issues as done whatever their status")
+}
+
+// Complete resolves settings from the configuration file and the selected
+// profile, with flags taking precedence. It must be called before the
+// command runs.
+func (b *BaseCommand) Complete(cmd *cobra.Command) error {
+	var settings config.Settings
+	if b.config != nil {
+		profile, err := b.config.Profile(b.Profile)
+		if err != nil {
+			return err
+		}
+		settings = profile
+	} else if b.Profile != "" {
+		return fmt.Errorf("profile %q not found: no configuration file loaded", b.Profile)
+	}
+
+	flags := cmd.Flags()
+	if verbose, err := flags.GetBool("verbose"); err == nil {
+		b.Verbose = verbose
+	}
+	if flag := flags.Lookup("output"); flag != nil {
+		format, err := output.ParseFormat(flag.Value.String())
+		if err != nil {
+			return err
+		}
+		b.Output = format
+	}
+	if !flags.Changed("project") && settings.Project != "" {
+		b.ProjectURL = settings.Project
+	}
+	if !flags.Changed("silent") && settings.Silent != nil {
+		b.Silent = *settings.Silent
+	}
+	if !flags.Changed("dry-run") && settings.DryRun != nil {
+		b.DryRun = *settings.DryRun
+	}
+	if !flags.Changed("field") && settings.IterationField != "" {
+		b.IterationField = settings.IterationField
+	}
+	if !flags.Changed("repo") && settings.Filters != nil {
+		b.Repositories = settings.Filters.Repositories
+	}
+	if !flags.Changed("include-types") && settings.Filters != nil && len(settings.Filters.Types) > 0 {
+		b.IncludeTypes = settings.Filters.Types
+	}
+	if len(b.IncludeTypes) > 0 {
+		kinds, err := projects.ParseItemKinds(b.IncludeTypes)
+		if err != nil {
+			return err
+		}
+		b.Kinds = kinds
+	}
+
+	rule, err := b.completionRule(cmd, settings.Completion)
+	if err != nil {
+		return err
+	}
+	b.Completion = rule
+
+	if b.requireProject && b.ProjectURL == "" {
+		return fmt.Errorf("a project is required: pass --project or set project in a configuration profile")
+	}
+	return nil
 }
 
-// RequireProject marks the project flag as required
-func (b *BaseCommand) RequireProject(cmd *cobra.Command) {
-	cmd.MarkFlagRequired("project")
+// EffectiveSettings returns the settings resolved by Complete in configuration
+// file form.
+func (b *BaseCommand) EffectiveSettings() config.Settings {
+	settings := config.Settings{
+		Project:        b.ProjectURL,
+		IterationField: b.IterationField,
+		Silent:         &b.Silent,
+		DryRun:         &b.DryRun,
+	}
+
+	if b.Completion != nil {
+		settings.Completion = &config.Completion{
+			Fields:       b.Completion.Fields,
+			DoneValues:   b.Completion.DoneValues,
+			ClosedIsDone: &b.Completion.ClosedIsDone,
+		}
+		if b.Completion.DonePattern != nil {
+			settings.Completion.DonePattern = b.Completion.DonePattern.String()
+		}
+	}
+
+	if len(b.Repositories) > 0 || len(b.IncludeTypes) > 0 {
+		settings.Filters = &config.Filters{
+			Repositories: b.Repositories,
+			Types:        b.IncludeTypes,
+		}
+	}
+
+	return settings
+}
+
+func (b *BaseCommand) completionRule(cmd *cobra.Command, cfg *config.Completion) (*projects.CompletionRule, error) {
+	rule := projects.DefaultCompletionRule()
+	pattern := ""
+
+	if cfg != nil {
+		if len(cfg.Fields) > 0 {
+			rule.Fields = cfg.Fields
+		}
+		if len(cfg.DoneValues) > 0 {
+			rule.DoneValues = cfg.DoneValues
+		}
+		if cfg.ClosedIsDone != nil {
+			rule.ClosedIsDone = *cfg.ClosedIsDone
+		}
+		pattern = cfg.DonePattern
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("done-field") {
+		rule.Fields = b.DoneFields
+	}
+	if flags.Changed("done-value") {
+		rule.DoneValues = b.DoneValues
+	}
+	if flags.Changed("closed-is-done") {
+		rule.ClosedIsDone = b.ClosedIsDone
+	}
+	if flags.Changed("done-pattern") {
+		pattern = b.DonePattern
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid done pattern %q: %w", pattern, err)
+		}
+		rule.DonePattern = re
+	}
+
+	return rule, nil
+}
+
+// RequireProject makes the project required, from either the --project flag
+// or the configuration profile
+func (b *BaseCommand) RequireProject() {
+	b.requireProject = true
+}
+
+// RouteOutput sends human-readable output to stderr when a structured output
+// format was requested, keeping stdout for the document itself.
+func (b *BaseCommand) RouteOutput() {
+	if b.Output != output.Text {
+		ui.SetOutput(os.Stderr)
+	} else {
+		ui.SetOutput(os.Stdout)
+	}
+}
+
+// WriteOutput writes doc to stdout in the requested structured format. It
+// does nothing for text output.
+func (b *BaseCommand) WriteOutput(doc interface{}) error {
+	if b.Output == output.Text {
+		return nil
+	}
+
+	w := b.stdout
+	if w == nil {
+		w = os.Stdout
+	}
+	if err := output.Write(w, b.Output, doc); err != nil {
+		return fmt.Errorf("failed to write %s output: %w", b.Output, err)
+	}
+	return nil
+}
+
+// Context returns the context for a command run derived from parent, which
+// is cancelled on interrupt, limited by --timeout when it is set.
+func (b *BaseCommand) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	if b.Timeout > 0 {
+		return context.WithTimeout(parent, b.Timeout)
+	}
+	return context.WithCancel(parent)
 }
 
 // GetGitHubClient creates and returns an authenticated GitHub client
-func (b *BaseCommand) GetGitHubClient() (*github.Client, error) {
-	return github.NewClient(b.Token)
+func (b *BaseCommand) GetGitHubClient(ctx context.Context) (*github.Client, error) {
+	if b.transport != nil {
+		client := github.NewClientWithTransport(b.transport)
+		if b.issues != nil {
+			client.SetIssueWriter(b.issues)
+		}
+		return client, nil
+	}
+
+	if b.Replay != "" {
+		replay, err := github.NewReplayTransport(b.Replay)
+		if err != nil {
+			return nil, err
+		}
+		return github.NewClientWithTransport(replay), nil
+	}
+
+	client, err := github.NewClient(ctx, b.Token, b.host())
+	if err != nil {
+		return nil, err
+	}
+
+	if b.Record != "" {
+		if err := client.Record(b.Record); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// NewManager returns a manager for the project with projectID that prints its
+// warnings, and with --verbose logs how it fetched items.
+func (b *BaseCommand) NewManager(client *github.Client, projectID string) *projects.Manager {
+	manager := projects.NewManager(client, projectID)
+	manager.OnWarning(func(message string) {
+		ui.Printf("⚠️  Warning: %s\n", message)
+	})
+	if b.Verbose {
+		manager.OnDebug(func(message string) {
+			log.Print(message)
+		})
+	}
+	return manager
+}
+
+// host returns the host of the project URL, or "" when it has none so that
+// the client picks its default.
+func (b *BaseCommand) host() string {
+	host, _, _, err := github.ParseProjectURL(b.ProjectURL)
+	if err != nil {
+		return ""
+	}
+	return host
 }
 
 // ParseProjectURL parses the project URL and returns owner and project number
-func (b *BaseCommand) ParseProjectURL(client *github.Client) (string, int, string, error) {
-	owner, numberStr, err := client.ParseProjectURL(b.ProjectURL)
+func (b *BaseCommand) ParseProjectURL(ctx context.Context, client *github.Client) (string, int, string, error) {
+	_, owner, numberStr, err := github.ParseProjectURL(b.ProjectURL)
 	if err != nil {
 		return "", 0, "", err
 	}
 
-	projectID, err := getProjectID(client, owner, numberStr)
+	projectID, err := getProjectID(ctx, client, owner, numberStr)
 	if err != nil {
 		return "", 0, "", err
 	}
@@ -63,35 +360,59 @@ func (b *BaseCommand) ParseProjectURL(client *github.Client) (string, int, strin
 	return owner, parseNumber(numberStr), projectID, nil
 }
 
+// GetIterations loads the iterations of the selected iteration field. When
+// the project has several iteration fields and none was selected, the user
+// is asked to choose one, or an error listing them is returned in silent mode.
+func (b *BaseCommand) GetIterations(ctx context.Context, manager *projects.Manager, prompter *ui.Prompter) (*projects.IterationInfo, error) {
+	info, err := manager.GetIterations(ctx, b.IterationField)
+
+	var ambiguous *projects.AmbiguousFieldError
+	if errors.As(err, &ambiguous) && !b.Silent {
+		field, chooseErr := prompter.ChooseField(ctx, ambiguous.Fields)
+		if chooseErr != nil {
+			return nil, chooseErr
+		}
+		b.IterationField = field.ID
+		info, err = manager.GetIterations(ctx, field.ID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get iterations: %w", err)
+	}
+	return info, nil
+}
+
 // Helper functions
-func getProjectID(client *github.Client, owner, numberStr string) (string, error) {
+func getProjectID(ctx context.Context, client *github.Client, owner, numberStr string) (string, error) {
 	number := parseNumber(numberStr)
-	projectResult, err := client.GraphQL(github.GetProjectQuery, map[string]interface{}{
+	// The owner is looked up as both a user and an organization, so one of
+	// the two lookups is expected to fail with NOT_FOUND
+	response, err := github.GraphQLResponse[github.ProjectResponse](ctx, client, github.GetProjectQuery, map[string]interface{}{
 		"owner":  owner,
 		"number": number,
 	})
 	if err != nil {
 		return "", err
 	}
+	projectResult := response.Data
 
 	// Try user projects first
-	if userData, ok := projectResult["data"].(map[string]interface{})["user"].(map[string]interface{}); ok {
-		if project, ok := userData["projectV2"].(map[string]interface{}); ok {
-			return project["id"].(string), nil
-		}
+	if projectResult.User != nil && projectResult.User.ProjectV2 != nil {
+		return projectResult.User.ProjectV2.ID, nil
 	}
 
 	// Try organization projects
-	if orgData, ok := projectResult["data"].(map[string]interface{})["organization"].(map[string]interface{}); ok {
-		if project, ok := orgData["projectV2"].(map[string]interface{}); ok {
-			return project["id"].(string), nil
-		}
+	if projectResult.Organization != nil && projectResult.Organization.ProjectV2 != nil {
+		return projectResult.Organization.ProjectV2.ID, nil
 	}
 
-	return "", fmt.Errorf("project not found")
+	if fatal := response.Errors.Fatal(); len(fatal) > 0 {
+		return "", fmt.Errorf("project %s/%s could not be loaded: %w", owner, numberStr, fatal)
+	}
+	return "", fmt.Errorf("project not found: neither data.user.projectV2 nor data.organization.projectV2 was returned for %s/%s", owner, numberStr)
 }
 
 func parseNumber(numberStr string) int {
 	number, _ := strconv.Atoi(numberStr)
 	return number
-}
\ No newline at end of file
+}
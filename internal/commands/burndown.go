@@ -0,0 +1,175 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/config"
+	"github.com/kriscoleman/gh-projects/internal/output"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// burndownHeight is the number of rows in a burndown chart in the terminal.
+const burndownHeight = 12
+
+// burndownOptions holds the flags specific to iteration burndown.
+type burndownOptions struct {
+	*BaseCommand
+	Iteration   string
+	PointsField string
+	Burnup      bool
+	SVG         string
+}
+
+func NewIterationBurndownCmd(cfg *config.Config) *cobra.Command {
+	opts := &burndownOptions{BaseCommand: &BaseCommand{config: cfg}}
+
+	cmd := &cobra.Command{
+		Use:   "burndown",
+		Short: "Chart the work remaining over the days of an iteration",
+		Long: `Work out how much work was left in an iteration at the start of each of
+its days and chart it against an ideal straight line, or with --burnup chart
+the work completed against the iteration's scope.
+
+Work is measured in a project number field given with --points-field, or in
+items when no field is given. An item counts as done from when it was closed;
+items done by their status alone count from their last change in the project,
+since GitHub does not report when a status changed. The scope is the work in
+the iteration now, so items rolled over out of it are not included.
+
+The chart is drawn in the terminal; --svg also writes it to an SVG file, and
+-o csv writes the numbers for each day.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(cmd); err != nil {
+				return err
+			}
+			ctx, cancel := opts.Context(cmd.Context())
+			defer cancel()
+			return runIterationBurndown(ctx, opts)
+		},
+	}
+
+	opts.AddCommonFlags(cmd)
+	opts.AddCompletionFlags(cmd)
+	opts.AddFieldFlag(cmd)
+	opts.AddFilterFlags(cmd)
+	opts.RequireProject()
+	cmd.Flags().StringVar(&opts.Iteration, "iteration", "current", "Iteration to chart, e.g. previous, @-2 or a title")
+	cmd.Flags().StringVar(&opts.PointsField, "points-field", "", "Number `field` measuring work, e.g. Estimate (default: count items)")
+	cmd.Flags().BoolVar(&opts.Burnup, "burnup", false, "Chart the work completed against the scope instead of the work remaining")
+	cmd.Flags().StringVar(&opts.SVG, "svg", "", "Also write the chart to `file` as SVG")
+
+	return cmd
+}
+
+func runIterationBurndown(ctx context.Context, opts *burndownOptions) error {
+	base := opts.BaseCommand
+	base.RouteOutput()
+
+	ui.Println("📉 GitHub Projects - Iteration Burndown")
+	ui.Println("=======================================")
+
+	client, err := base.GetGitHubClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize GitHub client: %w", err)
+	}
+
+	owner, number, projectID, err := base.ParseProjectURL(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	ui.Printf("📂 Project: %s/%d\n", owner, number)
+
+	manager := base.NewManager(client, projectID)
+
+	iterationInfo, err := base.GetIterations(ctx, manager, ui.NewPrompter())
+	if err != nil {
+		return err
+	}
+
+	iteration, err := iterationInfo.Resolve(opts.Iteration)
+	if err != nil {
+		return fmt.Errorf("invalid --iteration: %w", err)
+	}
+
+	pointsField := ""
+	if opts.PointsField != "" {
+		field, err := manager.GetNumberField(ctx, opts.PointsField)
+		if err != nil {
+			return fmt.Errorf("invalid --points-field: %w", err)
+		}
+		pointsField = field.Name
+	}
+
+	rule := base.Completion
+	if rule == nil {
+		rule = projects.DefaultCompletionRule()
+	}
+
+	ui.Printf("\n🔍 Fetching issues from %s...\n", iteration.Title)
+	issues, err := manager.GetIterationItems(ctx, iteration)
+	if err != nil {
+		return fmt.Errorf("failed to fetch issues: %w", err)
+	}
+	if len(base.Kinds) > 0 {
+		issues = projects.FilterByKind(issues, base.Kinds)
+	}
+	if len(base.Repositories) > 0 {
+		issues = projects.FilterByRepository(issues, base.Repositories)
+	}
+
+	report := newBurndownReport(projectSummary{
+		URL:    base.ProjectURL,
+		Owner:  owner,
+		Number: number,
+		ID:     projectID,
+	}, iterationInfo, iteration, issues, rule, pointsField, time.Now())
+	chart := report.chart(opts.Burnup)
+
+	if base.Output == output.Text {
+		var text bytes.Buffer
+		if err := chart.Text(&text, burndownHeight); err != nil {
+			return err
+		}
+		ui.Print("\n" + text.String())
+	}
+	if report.Unestimated > 0 {
+		ui.Printf("⚠️  %d items had no %s and were counted as 0\n", report.Unestimated, pointsField)
+	}
+	if report.Undated > 0 {
+		ui.Printf("⚠️  %d done items have no completion time and were counted as done at the end\n", report.Undated)
+	}
+
+	if opts.SVG != "" {
+		var svg bytes.Buffer
+		if err := chart.SVG(&svg); err != nil {
+			return err
+		}
+		if err := os.WriteFile(opts.SVG, svg.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write chart: %w", err)
+		}
+		ui.Printf("🖼️  Chart written to %s\n", opts.SVG)
+	}
+
+	return base.WriteOutput(report)
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"math"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/chart"
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+)
+
+// burndownReport is the document iteration burndown writes with --output.
+type burndownReport struct {
+	Project   projectSummary   `json:"project" yaml:"project"`
+	Field     string           `json:"field" yaml:"field"`
+	Iteration iterationSummary `json:"iteration" yaml:"iteration"`
+	// PointsField is the number field measuring work, or empty when items
+	// are counted.
+	PointsField string `json:"pointsField,omitempty" yaml:"pointsField,omitempty"`
+	// Scope is all the work in the iteration, done or not.
+	Scope float64 `json:"scope" yaml:"scope"`
+	// Unestimated counts the items without a value in PointsField.
+	Unestimated int `json:"unestimated,omitempty" yaml:"unestimated,omitempty"`
+	// Undated counts the done items whose completion time is unknown; they
+	// are taken as done at the end of the iteration.
+	Undated int            `json:"undated,omitempty" yaml:"undated,omitempty"`
+	Days    []*burndownDay `json:"days" yaml:"days"`
+}
+
+// burndownDay is the work left and done at the start of a day; the last one
+// is the end of the iteration. Remaining and Completed are unset for days
+// still to come.
+type burndownDay struct {
+	Date      string   `json:"date" yaml:"date"`
+	Ideal     float64  `json:"ideal" yaml:"ideal"`
+	Remaining *float64 `json:"remaining,omitempty" yaml:"remaining,omitempty"`
+	Completed *float64 `json:"completed,omitempty" yaml:"completed,omitempty"`
+}
+
+// newBurndownReport works out the work remaining in iteration from the start
+// of each of its days until its end, as of now. issues are the items in the
+// iteration and pointsField the number field measuring them, or "" to count
+// items.
+func newBurndownReport(project projectSummary, info *projects.IterationInfo, iteration *github.Iteration, issues []*github.Issue, rule *projects.CompletionRule, pointsField string, now time.Time) *burndownReport {
+	report := &burndownReport{
+		Project:     project,
+		Field:       info.FieldName,
+		Iteration:   summarizeIteration(iteration),
+		PointsField: pointsField,
+		Days:        []*burndownDay{},
+	}
+
+	type done struct {
+		at    time.Time
+		value float64
+	}
+	var finished []done
+
+	for _, issue := range issues {
+		value := 1.0
+		if pointsField != "" {
+			var estimated bool
+			if value, estimated = projects.GetNumberValue(issue, pointsField); !estimated {
+				report.Unestimated++
+			}
+		}
+		report.Scope += value
+
+		if !rule.IsDone(issue) {
+			continue
+		}
+		at, known := doneAt(issue)
+		if !known {
+			report.Undated++
+			at = iteration.EndDate().Add(-time.Nanosecond)
+		}
+		finished = append(finished, done{at: at, value: value})
+	}
+
+	start := iteration.StartDate
+	for day := 0; day <= iteration.Duration; day++ {
+		point := &burndownDay{Date: start.AddDate(0, 0, day).Format("2006-01-02")}
+		if iteration.Duration > 0 {
+			point.Ideal = round(report.Scope * (1 - float64(day)/float64(iteration.Duration)))
+		}
+		report.Days = append(report.Days, point)
+
+		// The day in progress shows the work done so far
+		cutoff := start.AddDate(0, 0, day)
+		if cutoff.After(now) {
+			if day == 0 || !start.AddDate(0, 0, day-1).Before(now) {
+				continue
+			}
+			cutoff = now
+		}
+
+		completed := 0.0
+		for _, d := range finished {
+			if d.at.Before(cutoff) {
+				completed += d.value
+			}
+		}
+		remaining := round(report.Scope - completed)
+		completed = round(completed)
+		point.Remaining, point.Completed = &remaining, &completed
+	}
+	return report
+}
+
+// doneAt returns when issue was done, and whether that is known. Closed
+// issues and pull requests were done when they were closed. The API doesn't
+// say when a status changed, so for items done by status alone the last
+// change to the project item stands in for it.
+func doneAt(issue *github.Issue) (time.Time, bool) {
+	if !issue.ClosedAt.IsZero() {
+		return issue.ClosedAt, true
+	}
+	if len(issue.ProjectItems.Nodes) > 0 && !issue.ProjectItems.Nodes[0].UpdatedAt.IsZero() {
+		return issue.ProjectItems.Nodes[0].UpdatedAt, true
+	}
+	return time.Time{}, false
+}
+
+// chart returns the report as a burndown chart of the work remaining, or a
+// burnup chart of the work completed against the scope.
+func (r *burndownReport) chart(burnup bool) *chart.Chart {
+	unit := "items"
+	if r.PointsField != "" {
+		unit = r.PointsField
+	}
+
+	c := &chart.Chart{Title: r.Iteration.Title + " burndown (" + unit + " remaining)"}
+	if burnup {
+		c.Title = r.Iteration.Title + " burnup (" + unit + " completed)"
+	}
+
+	ideal := make([]float64, len(r.Days))
+	actual := make([]float64, len(r.Days))
+	scope := make([]float64, len(r.Days))
+	for i, day := range r.Days {
+		date, _ := time.Parse("2006-01-02", day.Date)
+		c.Labels = append(c.Labels, date.Format("Jan 2"))
+
+		ideal[i], scope[i], actual[i] = day.Ideal, r.Scope, math.NaN()
+		switch {
+		case burnup && day.Completed != nil:
+			actual[i] = *day.Completed
+		case !burnup && day.Remaining != nil:
+			actual[i] = *day.Remaining
+		}
+	}
+
+	if burnup {
+		c.Series = []chart.Series{
+			{Name: "Scope", Values: scope, Marker: '-', Color: "#8c959f", Dashed: true},
+			{Name: "Completed", Values: actual, Marker: '●', Color: "#1a7f37"},
+		}
+	} else {
+		c.Series = []chart.Series{
+			{Name: "Ideal", Values: ideal, Marker: '·', Color: "#8c959f", Dashed: true},
+			{Name: "Remaining", Values: actual, Marker: '●', Color: "#0969da"},
+		}
+	}
+	return c
+}
+
+func (r *burndownReport) Header() []string {
+	return []string{"date", "ideal", "remaining", "completed", "scope"}
+}
+
+func (r *burndownReport) Rows() [][]string {
+	optional := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return formatNumber(*v)
+	}
+
+	rows := make([][]string, 0, len(r.Days))
+	for _, day := range r.Days {
+		rows = append(rows, []string{
+			day.Date, formatNumber(day.Ideal), optional(day.Remaining), optional(day.Completed), formatNumber(r.Scope),
+		})
+	}
+	return rows
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/output"
+)
+
+// newBurndownFake scripts a project whose four-day "Sprint 1", starting on
+// 2025-03-03, holds an issue estimated at 3 closed on its first day, an open
+// issue estimated at 2 marked done on its third day, an issue estimated at 5
+// still in progress, and a done draft issue with no estimate or date.
+func newBurndownFake() *github.FakeTransport {
+	fake := github.NewFakeTransport()
+	addProject(fake, `[
+		{"id":"F_iter","name":"Sprint","dataType":"ITERATION","configuration":{
+			"iterations":[],
+			"completedIterations":[{"id":"it-1","title":"Sprint 1","startDate":"2025-03-03","duration":4}]
+		}},
+		{"id":"F_estimate","name":"Estimate","dataType":"NUMBER"}
+	]`)
+	addItems(fake, `[
+			{"id":"PVTI_closed","updatedAt":"2025-03-06T09:00:00Z","content":{"__typename":"Issue","id":"I_1","number":1,"title":"Closed","state":"CLOSED","closedAt":"2025-03-03T10:00:00Z"},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldNumberValue","field":{"id":"F_estimate","name":"Estimate"},"number":3}
+			]}},
+			{"id":"PVTI_done","updatedAt":"2025-03-05T12:00:00Z","content":{"__typename":"Issue","id":"I_2","number":2,"title":"Done","state":"OPEN","closedAt":null},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":"Done"},
+				{"__typename":"ProjectV2ItemFieldNumberValue","field":{"id":"F_estimate","name":"Estimate"},"number":2}
+			]}},
+			{"id":"PVTI_open","updatedAt":"2025-03-04T12:00:00Z","content":{"__typename":"Issue","id":"I_3","number":3,"title":"Open","state":"OPEN"},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":"In Progress"},
+				{"__typename":"ProjectV2ItemFieldNumberValue","field":{"id":"F_estimate","name":"Estimate"},"number":5}
+			]}},
+			{"id":"PVTI_draft","content":{"__typename":"DraftIssue","id":"DI_4","title":"Draft"},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":"Done"}
+			]}}
+	]`)
+	return fake
+}
+
+func TestIterationBurndown(t *testing.T) {
+	tests := []struct {
+		name        string
+		pointsField string
+		scope       float64
+		want        string
+	}{
+		{
+			name:  "items",
+			scope: 4,
+			// The undated draft counts as done at the end
+			want: "2025-03-03 4/4, 2025-03-04 3/3, 2025-03-05 3/2, 2025-03-06 2/1, 2025-03-07 1/0",
+		},
+		{
+			name:        "points",
+			pointsField: "Estimate",
+			scope:       10,
+			want:        "2025-03-03 10/10, 2025-03-04 7/7.5, 2025-03-05 7/5, 2025-03-06 5/2.5, 2025-03-07 5/0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := &burndownOptions{Iteration: "Sprint 1", PointsField: tt.pointsField, BaseCommand: &BaseCommand{
+				ProjectURL: testProjectURL,
+				Output:     output.JSON,
+				stdout:     &stdout,
+				transport:  newBurndownFake(),
+			}}
+			if err := runIterationBurndown(context.Background(), opts); err != nil {
+				t.Fatalf("burndown: %v", err)
+			}
+
+			var report burndownReport
+			if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+				t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+			}
+
+			var days []string
+			for _, day := range report.Days {
+				if day.Remaining == nil {
+					t.Fatalf("no remaining work on %s of a finished iteration", day.Date)
+				}
+				days = append(days, fmt.Sprintf("%s %v/%v", day.Date, *day.Remaining, day.Ideal))
+			}
+			if got := strings.Join(days, ", "); got != tt.want {
+				t.Errorf("remaining/ideal = %s, want %s", got, tt.want)
+			}
+			if report.Scope != tt.scope || report.Undated != 1 {
+				t.Errorf("scope %v with %d undated items, want %v with 1", report.Scope, report.Undated, tt.scope)
+			}
+		})
+	}
+}
+
+func TestIterationBurndownSVG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "burnup.svg")
+	opts := &burndownOptions{Iteration: "Sprint 1", Burnup: true, SVG: path, BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		transport:  newBurndownFake(),
+	}}
+	if err := runIterationBurndown(context.Background(), opts); err != nil {
+		t.Fatalf("burndown: %v", err)
+	}
+
+	svg, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("no chart written: %v", err)
+	}
+	if !strings.Contains(string(svg), "Sprint 1 burnup (items completed)") {
+		t.Errorf("chart is not a burnup chart:\n%s", svg)
+	}
+}
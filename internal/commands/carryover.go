@@ -0,0 +1,191 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+)
+
+// Policies for items at the --max-carryovers limit.
+const (
+	// overMaxExclude never moves them again.
+	overMaxExclude = "exclude"
+	// overMaxFlag moves them only when confirmed interactively.
+	overMaxFlag = "flag"
+)
+
+// carryoverTracker returns where rollover counts carryovers, after checking
+// the carryover flags make sense together.
+func carryoverTracker(opts *rolloverOptions) (*projects.CarryoverTracker, error) {
+	tracker := &projects.CarryoverTracker{Field: opts.CarryoverField, Label: opts.CarryoverLabel}
+
+	if opts.MaxCarryovers < 0 {
+		return nil, fmt.Errorf("--max-carryovers must not be negative")
+	}
+	// Plans record only the moves, so apply would never raise the counts
+	if opts.PlanOut != "" && tracker.Enabled() {
+		return nil, fmt.Errorf("--carryover-field and --carryover-label cannot be used with --plan-out: iteration apply does not count carryovers")
+	}
+	if opts.MaxCarryovers > 0 && !tracker.Enabled() {
+		return nil, fmt.Errorf("--max-carryovers needs --carryover-field or --carryover-label to count carryovers")
+	}
+	switch opts.OverMax {
+	case "", overMaxExclude, overMaxFlag:
+	default:
+		return nil, fmt.Errorf("invalid --over-max %q: expected %s or %s", opts.OverMax, overMaxExclude, overMaxFlag)
+	}
+	return tracker, nil
+}
+
+// overLimit reports whether issue has been carried over too often to be
+// moved again without a second look.
+func (o *rolloverOptions) overLimit(issue *github.Issue) bool {
+	return o.MaxCarryovers > 0 && issue.Carryovers >= o.MaxCarryovers
+}
+
+// incrementCarryovers adds one to the carryover count of each of issues,
+// which were just moved, and returns how many could not be updated. A
+// failure is reported but does not undo the move. field is the number field
+// counting carryovers, or nil when they are counted in labels.
+func incrementCarryovers(ctx context.Context, client *github.Client, manager *projects.Manager, field *github.ProjectField, issues []*github.Issue) int {
+	var writer github.IssueWriter
+	if field == nil {
+		// Draft issues have no labels to count in
+		issues = annotatable(issues)
+		var err error
+		if writer, err = client.Issues(); err != nil {
+			ui.Printf("⚠️  Warning: carryover counts were not updated: %v\n", err)
+			return len(issues)
+		}
+	}
+
+	failed := 0
+	for i, issue := range issues {
+		if ctx.Err() != nil {
+			return failed + len(issues) - i
+		}
+
+		next := issue.Carryovers + 1
+		var err error
+		if field != nil {
+			for _, item := range issue.ProjectItems.Nodes {
+				if err = manager.UpdateItemNumber(ctx, item.ID, field.ID, float64(next)); err != nil {
+					break
+				}
+			}
+		} else {
+			err = setCarryoverLabel(ctx, writer, issue, next)
+		}
+
+		if err != nil {
+			ui.Printf("⚠️  Warning: %s was moved, but its carryover count was not updated: %v\n", issue.Ref(), err)
+			failed++
+			continue
+		}
+		issue.Carryovers = next
+	}
+	return failed
+}
+
+// setCarryoverLabel replaces the carryover label of issue with one counting
+// count carryovers. The new label is added first so the count is never lost.
+func setCarryoverLabel(ctx context.Context, writer github.IssueWriter, issue *github.Issue, count int) error {
+	owner, repo := issue.Repository.Owner.Login, issue.Repository.Name
+	label := projects.FormatCarryoverLabel(count)
+
+	if err := writer.AddLabels(ctx, owner, repo, issue.Number, []string{label}); err != nil {
+		return err
+	}
+	if _, old := projects.CarryoverLabel(issue); old != "" && old != label {
+		return writer.RemoveLabel(ctx, owner, repo, issue.Number, old)
+	}
+	return nil
+}
+
+// carryoverChange returns the journal record of the carryover count of issue
+// going from previous to its current count, or nil when the count did not
+// change. field is as for incrementCarryovers.
+func carryoverChange(issue *github.Issue, field *github.ProjectField, previous int) *journal.Carryover {
+	if issue.Carryovers == previous {
+		return nil
+	}
+	change := &journal.Carryover{From: previous, To: issue.Carryovers}
+	if field != nil {
+		change.FieldID = field.ID
+	} else {
+		change.Owner, change.Repo, change.Number = issue.Repository.Owner.Login, issue.Repository.Name, issue.Number
+	}
+	return change
+}
+
+// restoreCarryovers makes the carryover change recorded with each applied
+// entry of changes, which undo has just moved back, and returns how many
+// could not be made. The record of a change that failed is dropped so the
+// journal only claims what was done.
+func restoreCarryovers(ctx context.Context, client *github.Client, manager *projects.Manager, changes *journal.Journal) int {
+	var writer github.IssueWriter
+	var writerErr error
+	failed := 0
+	for i := range changes.Entries {
+		entry := &changes.Entries[i]
+		change := entry.Carryover
+		if change == nil || !entry.Applied() {
+			continue
+		}
+
+		var err error
+		switch {
+		case ctx.Err() != nil:
+			err = context.Cause(ctx)
+		case change.FieldID != "":
+			err = manager.UpdateItemNumber(ctx, entry.ItemID, change.FieldID, float64(change.To))
+		default:
+			if writer == nil && writerErr == nil {
+				writer, writerErr = client.Issues()
+			}
+			if err = writerErr; err == nil {
+				err = setRecordedCarryoverLabel(ctx, writer, change)
+			}
+		}
+
+		if err != nil {
+			ui.Printf("⚠️  Warning: %s was moved back, but its carryover count was not restored: %v\n", describeEntry(*entry), err)
+			entry.Carryover = nil
+			failed++
+		}
+	}
+	return failed
+}
+
+// setRecordedCarryoverLabel swaps the carryover label counting change.From
+// for the one counting change.To, adding first so the count is never lost.
+func setRecordedCarryoverLabel(ctx context.Context, writer github.IssueWriter, change *journal.Carryover) error {
+	if change.To > 0 {
+		label := projects.FormatCarryoverLabel(change.To)
+		if err := writer.AddLabels(ctx, change.Owner, change.Repo, change.Number, []string{label}); err != nil {
+			return err
+		}
+	}
+	if change.From > 0 && change.From != change.To {
+		return writer.RemoveLabel(ctx, change.Owner, change.Repo, change.Number, projects.FormatCarryoverLabel(change.From))
+	}
+	return nil
+}
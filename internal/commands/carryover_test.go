@@ -0,0 +1,301 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+)
+
+// newCarryoverFake scripts a project whose finished "Sprint 1" holds two open
+// issues in acme/web: #1, carried over once, and #8, carried over three
+// times. Both counts are kept in a "Carryovers" number field and in
+// carryover labels.
+func newCarryoverFake() *github.FakeTransport {
+	fake := github.NewFakeTransport()
+	addProject(fake, fmt.Sprintf(`[
+		{"id":"F_iter","name":"Sprint","dataType":"ITERATION","configuration":{
+			"iterations":[{"id":"it-2","title":"Sprint 2","startDate":%q,"duration":14}],
+			"completedIterations":[{"id":"it-1","title":"Sprint 1","startDate":%q,"duration":14}]
+		}},
+		{"id":"F_carry","name":"Carryovers","dataType":"NUMBER"}
+	]`, day(-3), day(-17)))
+	addItems(fake, `[
+			{"id":"PVTI_open","content":{"__typename":"Issue","id":"I_1","number":1,"title":"Open","state":"OPEN","repository":{"name":"web","owner":{"login":"acme"}},
+				"labels":{"nodes":[{"name":"bug"},{"name":"carryover:1"}]}},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldNumberValue","field":{"id":"F_carry","name":"Carryovers"},"number":1}
+			]}},
+			{"id":"PVTI_stuck","content":{"__typename":"Issue","id":"I_8","number":8,"title":"Stuck","state":"OPEN","repository":{"name":"web","owner":{"login":"acme"}},
+				"labels":{"nodes":[{"name":"carryover:3"}]}},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldNumberValue","field":{"id":"F_carry","name":"Carryovers"},"number":3}
+			]}}
+	]`)
+	for _, id := range []string{"PVTI_open", "PVTI_stuck"} {
+		fake.Add(github.UpdateItemIterationMutation, map[string]interface{}{
+			"projectId":   testProjectID,
+			"itemId":      id,
+			"fieldId":     "F_iter",
+			"iterationId": "it-2",
+		}, fmt.Sprintf(`{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":%q}}}}`, id))
+	}
+	addBatchMove(fake, "it-2", "PVTI_open", "PVTI_stuck")
+	for id, count := range map[string]int{"PVTI_open": 2, "PVTI_stuck": 4} {
+		fake.Add(github.UpdateItemNumberMutation, map[string]interface{}{
+			"projectId": testProjectID,
+			"itemId":    id,
+			"fieldId":   "F_carry",
+			"number":    count,
+		}, fmt.Sprintf(`{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":%q}}}}`, id))
+	}
+	return fake
+}
+
+// numberUpdates returns the carryover counts sent for each item.
+func numberUpdates(fake *github.FakeTransport) map[string]interface{} {
+	updates := make(map[string]interface{})
+	for _, call := range fake.CallsFor(github.UpdateItemNumberMutation) {
+		updates[call.Variables["itemId"].(string)] = call.Variables["number"]
+	}
+	return updates
+}
+
+func TestIterationRolloverCarryovers(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    rolloverOptions
+		moved   []string
+		numbers map[string]interface{}
+		labels  []github.FakeIssueCall
+	}{
+		{
+			name:    "field",
+			opts:    rolloverOptions{CarryoverField: "carryovers"},
+			moved:   []string{"PVTI_open", "PVTI_stuck"},
+			numbers: map[string]interface{}{"PVTI_open": float64(2), "PVTI_stuck": float64(4)},
+		},
+		{
+			name:    "field by ID",
+			opts:    rolloverOptions{CarryoverField: "F_carry", MaxCarryovers: 3},
+			moved:   []string{"PVTI_open"},
+			numbers: map[string]interface{}{"PVTI_open": float64(2)},
+		},
+		{
+			name:  "label",
+			opts:  rolloverOptions{CarryoverLabel: true, MaxCarryovers: 3},
+			moved: []string{"PVTI_open"},
+			labels: []github.FakeIssueCall{
+				{Owner: "acme", Repo: "web", Number: 1, Labels: []string{"carryover:2"}},
+				{Owner: "acme", Repo: "web", Number: 1, Removed: "carryover:1"},
+			},
+		},
+		{
+			// Silent runs cannot ask, so flagged items stay put
+			name:    "flag",
+			opts:    rolloverOptions{CarryoverField: "Carryovers", MaxCarryovers: 3, OverMax: overMaxFlag},
+			moved:   []string{"PVTI_open"},
+			numbers: map[string]interface{}{"PVTI_open": float64(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newCarryoverFake()
+			writer := &github.FakeIssueWriter{}
+			opts := tt.opts
+			opts.From, opts.To = "previous", "current"
+			opts.BaseCommand = &BaseCommand{
+				ProjectURL: testProjectURL,
+				Silent:     true,
+				transport:  fake,
+				issues:     writer,
+			}
+
+			if err := runIterationRollover(context.Background(), &opts); err != nil {
+				t.Fatalf("rollover: %v", err)
+			}
+
+			if got := movedItems(fake); fmt.Sprint(got) != fmt.Sprint(tt.moved) {
+				t.Errorf("moved %v, want %v", got, tt.moved)
+			}
+			if got := numberUpdates(fake); fmt.Sprint(got) != fmt.Sprint(tt.numbers) {
+				t.Errorf("carryover counts set = %v, want %v", got, tt.numbers)
+			}
+			if got := writer.Calls(); fmt.Sprint(got) != fmt.Sprint(tt.labels) {
+				t.Errorf("label changes = %+v, want %+v", got, tt.labels)
+			}
+		})
+	}
+}
+
+func TestIterationRolloverMaxCarryoversNeedsTracking(t *testing.T) {
+	fake := newCarryoverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", MaxCarryovers: 3, BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err == nil {
+		t.Fatal("rollover accepted --max-carryovers without a way to count carryovers")
+	}
+	if got := len(fake.Calls()); got != 0 {
+		t.Errorf("sent %d requests before rejecting the flags, want none", got)
+	}
+}
+
+func TestIterationRolloverCarryoversWithPlanOut(t *testing.T) {
+	fake := newCarryoverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", CarryoverLabel: true, PlanOut: filepath.Join(t.TempDir(), "plan.json"), BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err == nil {
+		t.Fatal("rollover accepted --carryover-label with --plan-out")
+	}
+	if got := len(fake.Calls()); got != 0 {
+		t.Errorf("sent %d requests before rejecting the flags, want none", got)
+	}
+}
+
+func TestIterationUndoCarryovers(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      rolloverOptions
+		carryover journal.Carryover
+		numbers   map[string]interface{}
+		labels    []github.FakeIssueCall
+	}{
+		{
+			name:      "field",
+			opts:      rolloverOptions{CarryoverField: "Carryovers"},
+			carryover: journal.Carryover{FieldID: "F_carry", From: 1, To: 2},
+			numbers:   map[string]interface{}{"PVTI_open": float64(1)},
+		},
+		{
+			name:      "label",
+			opts:      rolloverOptions{CarryoverLabel: true},
+			carryover: journal.Carryover{Owner: "acme", Repo: "web", Number: 1, From: 1, To: 2},
+			labels: []github.FakeIssueCall{
+				{Owner: "acme", Repo: "web", Number: 1, Labels: []string{"carryover:1"}},
+				{Owner: "acme", Repo: "web", Number: 1, Removed: "carryover:2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			opts := tt.opts
+			opts.From, opts.To, opts.MaxCarryovers = "previous", "current", 3
+			opts.Journal = filepath.Join(dir, "rollover.json")
+			opts.BaseCommand = &BaseCommand{
+				ProjectURL: testProjectURL,
+				Silent:     true,
+				transport:  newCarryoverFake(),
+				issues:     &github.FakeIssueWriter{},
+			}
+			if err := runIterationRollover(context.Background(), &opts); err != nil {
+				t.Fatalf("rollover: %v", err)
+			}
+
+			recorded, err := journal.Load(opts.Journal)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(recorded.Entries) != 1 || recorded.Entries[0].Carryover == nil || *recorded.Entries[0].Carryover != tt.carryover {
+				t.Fatalf("journal entries = %+v, want PVTI_open recorded with carryover %+v", recorded.Entries, tt.carryover)
+			}
+
+			fake := newCarryoverFake()
+			fake.Add(github.GetItemIterationsQuery, map[string]interface{}{"ids": []string{"PVTI_open"}, "field": "Sprint"},
+				`{"data":{"nodes":[{"id":"PVTI_open","fieldValueByName":{"iterationId":"it-2","title":"Sprint 2"}}]}}`)
+			fake.Add(github.UpdateItemIterationMutation, map[string]interface{}{
+				"projectId":   testProjectID,
+				"itemId":      "PVTI_open",
+				"fieldId":     "F_iter",
+				"iterationId": "it-1",
+			}, `{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_open"}}}}`)
+			fake.Add(github.UpdateItemNumberMutation, map[string]interface{}{
+				"projectId": testProjectID,
+				"itemId":    "PVTI_open",
+				"fieldId":   "F_carry",
+				"number":    1,
+			}, `{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_open"}}}}`)
+			writer := &github.FakeIssueWriter{}
+			undo := &undoOptions{Journal: filepath.Join(dir, "undo.json"), BaseCommand: &BaseCommand{
+				Silent:    true,
+				transport: fake,
+				issues:    writer,
+			}}
+			if err := runIterationUndo(context.Background(), undo, opts.Journal); err != nil {
+				t.Fatalf("undo: %v", err)
+			}
+
+			if got := numberUpdates(fake); fmt.Sprint(got) != fmt.Sprint(tt.numbers) {
+				t.Errorf("carryover counts set = %v, want %v", got, tt.numbers)
+			}
+			if got := writer.Calls(); fmt.Sprint(got) != fmt.Sprint(tt.labels) {
+				t.Errorf("label changes = %+v, want %+v", got, tt.labels)
+			}
+
+			undone, err := journal.Load(undo.Journal)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(undone.Entries) != 1 || undone.Entries[0].Carryover == nil || *undone.Entries[0].Carryover != *tt.carryover.Reverse() {
+				t.Errorf("undo journal entries = %+v, want the carryover reversed", undone.Entries)
+			}
+		})
+	}
+}
+
+func TestIterationRolloverInterruptedFollowUps(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := newCarryoverFake()
+	writer := &github.FakeIssueWriter{}
+	opts := &rolloverOptions{From: "previous", To: "current", BatchSize: 1, CarryoverField: "Carryovers", Comment: true, BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  &interruptingTransport{Transport: fake, cancel: cancel},
+		issues:     writer,
+	}}
+
+	if err := runIterationRollover(ctx, opts); !errors.Is(err, context.Canceled) {
+		t.Fatalf("rollover error = %v, want it to report the interrupt", err)
+	}
+
+	// The item moved before the interrupt still gets its count and comment
+	if got := movedItems(fake); fmt.Sprint(got) != "[PVTI_open]" {
+		t.Errorf("moved %v, want only the item in flight when interrupted", got)
+	}
+	if got := numberUpdates(fake); fmt.Sprint(got) != fmt.Sprint(map[string]interface{}{"PVTI_open": float64(2)}) {
+		t.Errorf("carryover counts set = %v, want PVTI_open raised to 2", got)
+	}
+	if got := writer.Calls(); len(got) != 1 || got[0].Number != 1 || got[0].Body == "" {
+		t.Errorf("issue changes = %+v, want a comment on #1", got)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+)
+
+// Results for a change replayed by undo or apply.
+const (
+	resultRestored = "restored"
+	resultSkipped  = "skipped"
+	// resultRefused marks items apply left alone because they changed after
+	// the plan was made.
+	resultRefused = "refused"
+)
+
+// changeItem is a recorded or planned change to an item and what undo or
+// apply did with it.
+type changeItem struct {
+	ItemID    string `json:"itemId" yaml:"itemId"`
+	Ref       string `json:"ref,omitempty" yaml:"ref,omitempty"`
+	Title     string `json:"title,omitempty" yaml:"title,omitempty"`
+	Iteration string `json:"iteration" yaml:"iteration"`
+	Target    string `json:"targetIteration" yaml:"targetIteration"`
+	Result    string `json:"result,omitempty" yaml:"result,omitempty"`
+	Reason    string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// changeReport is the document undo and apply write with --output.
+type changeReport struct {
+	Journal string        `json:"journal,omitempty" yaml:"journal,omitempty"`
+	Plan    string        `json:"plan,omitempty" yaml:"plan,omitempty"`
+	Project string        `json:"project" yaml:"project"`
+	Field   string        `json:"field" yaml:"field"`
+	DryRun  bool          `json:"dryRun" yaml:"dryRun"`
+	Items   []*changeItem `json:"items" yaml:"items"`
+}
+
+func (r *changeReport) Header() []string {
+	return []string{"item_id", "ref", "title", "iteration", "target_iteration", "result", "reason"}
+}
+
+func (r *changeReport) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		rows = append(rows, []string{item.ItemID, item.Ref, item.Title, item.Iteration, item.Target, item.Result, item.Reason})
+	}
+	return rows
+}
+
+// moveCounts tallies what moveItems did.
+type moveCounts struct {
+	Moved        int
+	Failed       int
+	NotAttempted int
+}
+
+// moveItems moves the item of each entry in moves from its FromIterationID
+// to its ToIterationID, marking items[i] with result once moves[i] succeeds.
+// progress has moves journaled as pending and is settled batch by batch.
+func moveItems(ctx context.Context, manager *projects.Manager, fieldID string, moves []journal.Entry, items []*changeItem, batchSize int, result string, progress *journalProgress) moveCounts {
+	updates := make([]projects.ItemUpdate, len(moves))
+	for i, move := range moves {
+		updates[i] = projects.ItemUpdate{ItemID: move.ItemID, IterationID: move.ToIterationID}
+	}
+
+	var counts moveCounts
+	results := manager.UpdateItemIterations(ctx, fieldID, updates, batchSize, progress.settle)
+	for i, res := range results {
+		move, item := moves[i], items[i]
+		switch {
+		case errors.Is(res.Err, projects.ErrNotAttempted):
+			counts.NotAttempted++
+			item.Result = resultNotAttempted
+		case res.Err != nil:
+			ui.Printf("❌ Failed to move %s: %v\n", describeEntry(move), res.Err)
+			counts.Failed++
+			item.Result = resultFailed
+			item.Reason = res.Err.Error()
+		default:
+			counts.Moved++
+			ui.Printf("✅ Moved %s to %s\n", describeEntry(move), move.ToIteration)
+			item.Result = result
+		}
+	}
+	return counts
+}
+
+// showMoveSummary prints what moveItems did and how many items were left
+// out beforehand, with moved and skipped describing each, e.g. "moved back"
+// and "skipped".
+func showMoveSummary(counts moveCounts, moved string, skipped int, skippedAs string) {
+	ui.Println("\n" + strings.Repeat("=", 50))
+	ui.Println("📊 Summary")
+	ui.Println(strings.Repeat("=", 50))
+	ui.Printf("Items %s: %d\n", moved, counts.Moved)
+	if counts.Failed > 0 {
+		ui.Printf("Items that failed to move: %d\n", counts.Failed)
+	}
+	if counts.NotAttempted > 0 {
+		ui.Printf("Items not moved because of the interrupt: %d\n", counts.NotAttempted)
+	}
+	ui.Printf("Items %s: %d\n", skippedAs, skipped)
+}
+
+// iterationName returns the title of the iteration with id, falling back to
+// the title recorded earlier once it no longer exists.
+func iterationName(info *projects.IterationInfo, id, recorded string) string {
+	if id == "" {
+		return "no iteration"
+	}
+	for _, iteration := range info.Iterations {
+		if iteration.ID == id {
+			return iteration.Title
+		}
+	}
+	if recorded != "" {
+		return recorded
+	}
+	return id
+}
+
+func describeEntry(entry journal.Entry) string {
+	if entry.Ref == "" {
+		return entry.ItemID
+	}
+	return entry.Ref + ": " + entry.Title
+}
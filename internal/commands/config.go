@@ -0,0 +1,147 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kriscoleman/gh-projects/internal/config"
+	"github.com/kriscoleman/gh-projects/internal/output"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func NewConfigCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect gh-projects configuration",
+		Long: `Commands for inspecting the configuration loaded from
+$XDG_CONFIG_HOME/gh-projects/config.yaml and the repo-local ` + config.FileName + ` file.`,
+	}
+
+	cmd.AddCommand(NewConfigShowCmd(cfg))
+	return cmd
+}
+
+func NewConfigShowCmd(cfg *config.Config) *cobra.Command {
+	base := &BaseCommand{config: cfg}
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration",
+		Long: `Print the configuration a command would run with: the configuration
+files merged together, overlaid with the selected profile and any flags.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := base.Complete(cmd); err != nil {
+				return err
+			}
+			return runConfigShow(base)
+		},
+	}
+
+	base.AddCommonFlags(cmd)
+	base.AddFieldFlag(cmd)
+	base.AddCompletionFlags(cmd)
+	base.AddFilterFlags(cmd)
+
+	return cmd
+}
+
+// configReport is the document config show writes with --output.
+type configReport struct {
+	Paths    []string        `json:"paths" yaml:"paths"`
+	Profile  string          `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Settings config.Settings `json:"settings" yaml:"settings"`
+}
+
+func (r *configReport) Header() []string {
+	return []string{"setting", "value"}
+}
+
+func (r *configReport) Rows() [][]string {
+	settings := r.Settings
+	rows := [][]string{
+		{"project", settings.Project},
+		{"iteration-field", settings.IterationField},
+		{"silent", formatFlag(settings.Silent)},
+		{"dry-run", formatFlag(settings.DryRun)},
+	}
+	if completion := settings.Completion; completion != nil {
+		rows = append(rows,
+			[]string{"completion.fields", strings.Join(completion.Fields, ",")},
+			[]string{"completion.done-values", strings.Join(completion.DoneValues, ",")},
+			[]string{"completion.done-pattern", completion.DonePattern},
+			[]string{"completion.closed-is-done", formatFlag(completion.ClosedIsDone)},
+		)
+	}
+	if filters := settings.Filters; filters != nil {
+		rows = append(rows,
+			[]string{"filters.repositories", strings.Join(filters.Repositories, ",")},
+			[]string{"filters.types", strings.Join(filters.Types, ",")},
+		)
+	}
+	return rows
+}
+
+// formatFlag returns the value of an optional setting, or "" when it is
+// unset.
+func formatFlag(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+func runConfigShow(base *BaseCommand) error {
+	base.RouteOutput()
+
+	report := &configReport{Paths: []string{}, Profile: base.Profile, Settings: base.EffectiveSettings()}
+	if base.config != nil {
+		if report.Profile == "" {
+			report.Profile = base.config.DefaultProfile
+		}
+		if len(base.config.Paths) > 0 {
+			report.Paths = base.config.Paths
+		}
+	}
+
+	if len(report.Paths) == 0 {
+		ui.Println("# No configuration files found")
+	} else {
+		ui.Printf("# Loaded from: %s\n", strings.Join(report.Paths, ", "))
+	}
+	if report.Profile != "" {
+		ui.Printf("# Profile: %s\n", report.Profile)
+	}
+	if base.Output != output.Text {
+		return base.WriteOutput(report)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(report.Settings); err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	ui.Print(buf.String())
+	return nil
+}
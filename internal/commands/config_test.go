@@ -0,0 +1,66 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/kriscoleman/gh-projects/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestConfigShow(t *testing.T) {
+	cfg := &config.Config{Paths: []string{"/home/octocat/.config/gh-projects/config.yaml"}}
+	cfg.DefaultProfile = "team"
+	cfg.Profiles = map[string]config.Settings{"team": {Project: testProjectURL, IterationField: "Sprint"}}
+
+	if NewConfigShowCmd(cfg).Flags().Lookup("field") == nil {
+		t.Fatal("config show has no --field flag")
+	}
+
+	// The flags config show registers, on a BaseCommand whose output is
+	// captured
+	var stdout bytes.Buffer
+	base := &BaseCommand{config: cfg, stdout: &stdout}
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", "", "")
+	base.AddCommonFlags(cmd)
+	base.AddFieldFlag(cmd)
+	base.AddCompletionFlags(cmd)
+	base.AddFilterFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--field", "Release Train", "--output", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.Complete(cmd); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if err := runConfigShow(base); err != nil {
+		t.Fatalf("config show: %v", err)
+	}
+
+	var report configReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+	}
+	if report.Profile != "team" || len(report.Paths) != 1 {
+		t.Errorf("report = %+v, want the team profile from one file", report)
+	}
+	if report.Settings.Project != testProjectURL || report.Settings.IterationField != "Release Train" {
+		t.Errorf("settings = %+v, want the profile's project and the --field override", report.Settings)
+	}
+}
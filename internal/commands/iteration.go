@@ -15,114 +15,539 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
-	"github.com/spf13/cobra"
+	"github.com/kriscoleman/gh-projects/internal/config"
 	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/plan"
 	"github.com/kriscoleman/gh-projects/internal/projects"
 	"github.com/kriscoleman/gh-projects/internal/ui"
+	"github.com/spf13/cobra"
 )
 
-func NewIterationCmd() *cobra.Command {
+func NewIterationCmd(cfg *config.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "iteration",
 		Short: "Manage project iterations",
 		Long:  `Commands for managing GitHub project iterations.`,
 	}
 
-	cmd.AddCommand(NewIterationRolloverCmd())
+	cmd.AddCommand(NewIterationRolloverCmd(cfg))
+	cmd.AddCommand(NewIterationApplyCmd(cfg))
+	cmd.AddCommand(NewIterationUndoCmd(cfg))
+	cmd.AddCommand(NewIterationReportCmd(cfg))
+	cmd.AddCommand(NewIterationVelocityCmd(cfg))
+	cmd.AddCommand(NewIterationBurndownCmd(cfg))
 	return cmd
 }
 
-func NewIterationRolloverCmd() *cobra.Command {
-	base := &BaseCommand{}
+// rolloverOptions holds the flags specific to iteration rollover.
+type rolloverOptions struct {
+	*BaseCommand
+	From      string
+	To        string
+	Since     string
+	AllPast   bool
+	BatchSize int
+	Journal   string
+	PlanOut   string
+
+	Comment         bool
+	CommentTemplate string
+	Labels          []string
+
+	CarryoverField string
+	CarryoverLabel bool
+	MaxCarryovers  int
+	OverMax        string
+}
+
+func NewIterationRolloverCmd(cfg *config.Config) *cobra.Command {
+	opts := &rolloverOptions{BaseCommand: &BaseCommand{config: cfg}}
 
 	cmd := &cobra.Command{
 		Use:   "rollover",
-		Short: "Roll over incomplete issues from previous iteration",
-		Long: `Automatically reassign incomplete issues from the previous iteration 
-to the current iteration in GitHub Projects.`,
+		Short: "Roll over incomplete items from previous iteration",
+		Long: `Automatically reassign incomplete issues, pull requests and draft issues
+from the previous iteration to the current iteration in GitHub Projects.
+
+Use --from and --to to pick other iterations. Both accept an iteration title,
+an iteration ID, "previous", "current", "next", or an offset from the current
+iteration such as @-2 or @1.
+
+Use --all-past to gather items stranded in every completed iteration, or
+--since to limit that to iterations starting on or after a date or iteration.
+
+Pressing Ctrl-C, answering "q" or reaching --timeout stops the rollover: no
+further items are moved, updates already sent are allowed to finish, and the
+summary reports what did and didn't move.
+
+Every item moved is recorded in a journal, which "iteration undo" can use to
+move the items back.
+
+With --comment or --label, every issue and pull request moved also gets a
+comment such as "Carried over from Sprint 23 to Sprint 24" or the given
+labels, so that the people following it notice.
+
+With --plan-out nothing is moved: the items selected are written to a plan
+file instead, to be reviewed and then applied with "iteration apply".
+
+With --carryover-field or --carryover-label, every item moved has its
+carryover count raised by one, kept in a project number field or in a
+"carryover:N" label. Items that have reached --max-carryovers are then left
+out (--over-max exclude) or only moved when confirmed one by one
+(--over-max flag).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runIterationRollover(base)
+			if err := opts.Complete(cmd); err != nil {
+				return err
+			}
+			ctx, cancel := opts.Context(cmd.Context())
+			defer cancel()
+			return runIterationRollover(ctx, opts)
 		},
 	}
 
-	base.AddCommonFlags(cmd)
-	base.RequireProject(cmd)
+	opts.AddCommonFlags(cmd)
+	opts.AddCompletionFlags(cmd)
+	opts.AddFieldFlag(cmd)
+	opts.AddFilterFlags(cmd)
+	opts.RequireProject()
+	cmd.Flags().StringVar(&opts.From, "from", "previous", "Iteration to move incomplete items out of")
+	cmd.Flags().StringVar(&opts.To, "to", "current", "Iteration to move incomplete items into")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Gather items from every completed iteration starting on or after this `iteration or date` (YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&opts.AllPast, "all-past", false, "Gather items from every completed iteration")
+	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", projects.DefaultBatchSize, fmt.Sprintf("Number of items to move per GraphQL request (1 to %d; 1 disables batching)", projects.MaxBatchSize))
+	cmd.Flags().StringVar(&opts.Journal, "journal", "", "Write the journal to `file` instead of the journal directory")
+	cmd.Flags().StringVar(&opts.PlanOut, "plan-out", "", "Write the items that would be moved to `file` for \"iteration apply\" instead of moving them")
+	cmd.Flags().BoolVar(&opts.Comment, "comment", false, "Comment on every issue and pull request moved")
+	cmd.Flags().StringVar(&opts.CommentTemplate, "comment-template", "", "Go `template` for the --comment text, with .From, .To, .Ref, .Title, .Project and .Carryovers (default \""+defaultCommentTemplate+"\")")
+	cmd.Flags().StringSliceVar(&opts.Labels, "label", nil, "Add these labels to every issue and pull request moved, e.g. carryover")
+	cmd.Flags().StringVar(&opts.CarryoverField, "carryover-field", "", "Count carryovers in this project number `field`, raising it on every move")
+	cmd.Flags().BoolVar(&opts.CarryoverLabel, "carryover-label", false, "Count carryovers in a \"carryover:N\" label, raising it on every move")
+	cmd.Flags().IntVar(&opts.MaxCarryovers, "max-carryovers", 0, "Stop moving items carried over this many times (0 for no limit)")
+	cmd.Flags().StringVar(&opts.OverMax, "over-max", overMaxExclude, "What to do with items at --max-carryovers: exclude them, or flag them and ask before moving")
+	cmd.MarkFlagsMutuallyExclusive("from", "since", "all-past")
+	cmd.MarkFlagsMutuallyExclusive("carryover-field", "carryover-label")
+	cmd.MarkFlagsMutuallyExclusive("plan-out", "journal")
 
 	return cmd
 }
 
-func runIterationRollover(base *BaseCommand) error {
-	fmt.Println("🚀 GitHub Projects - Iteration Rollover")
-	fmt.Println("======================================")
+func runIterationRollover(ctx context.Context, opts *rolloverOptions) error {
+	base := opts.BaseCommand
+	base.RouteOutput()
+
+	ui.Println("🚀 GitHub Projects - Iteration Rollover")
+	ui.Println("======================================")
+
+	audit, err := newAuditTrail(opts)
+	if err != nil {
+		return err
+	}
+	tracker, err := carryoverTracker(opts)
+	if err != nil {
+		return err
+	}
 
-	client, err := base.GetGitHubClient()
+	client, err := base.GetGitHubClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to initialize GitHub client: %w", err)
 	}
 
-	owner, number, projectID, err := base.ParseProjectURL(client)
+	owner, number, projectID, err := base.ParseProjectURL(ctx, client)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("📂 Project: %s/%d\n", owner, number)
+	ui.Printf("📂 Project: %s/%d\n", owner, number)
 
-	manager := projects.NewManager(client, projectID)
+	manager := base.NewManager(client, projectID)
 
-	iterationInfo, err := manager.GetIterations()
+	prompter := ui.NewPrompter()
+
+	iterationInfo, err := base.GetIterations(ctx, manager, prompter)
 	if err != nil {
-		return fmt.Errorf("failed to get iterations: %w", err)
+		return err
+	}
+
+	sources, to, err := resolveRolloverIterations(iterationInfo, opts)
+	if err != nil {
+		return err
+	}
+
+	var carryoverField *github.ProjectField
+	if tracker.Field != "" {
+		carryoverField, err = manager.GetNumberField(ctx, tracker.Field)
+		if err != nil {
+			return fmt.Errorf("invalid --carryover-field: %w", err)
+		}
+		// Values are matched by name, and the flag may have held the ID
+		tracker.Field = carryoverField.Name
+	}
+
+	rule := base.Completion
+	if rule == nil {
+		rule = projects.DefaultCompletionRule()
 	}
 
-	ui.PrintIterationInfo(iterationInfo)
+	ui.PrintIterationInfo(sources, to)
 
-	fmt.Println("\n🔍 Fetching issues from previous iteration...")
-	issues, err := manager.GetIterationItems(iterationInfo.Previous.ID)
+	// Writing a plan moves nothing, like a dry run
+	planning := opts.PlanOut != ""
+	dryRun := base.DryRun || planning
+
+	report := newRolloverReport(projectSummary{
+		URL:    base.ProjectURL,
+		Owner:  owner,
+		Number: number,
+		ID:     projectID,
+	}, iterationInfo, sources, to, dryRun)
+
+	sourceIDs := make([]string, len(sources))
+	for i, source := range sources {
+		sourceIDs[i] = source.ID
+	}
+
+	ui.Printf("\n🔍 Fetching issues from %s...\n", iterationTitles(sources))
+	issues, err := manager.GetIterationItems(ctx, sources...)
 	if err != nil {
 		return fmt.Errorf("failed to fetch issues: %w", err)
 	}
+	sortBySource(issues, sourceIDs)
+
+	if len(base.Kinds) > 0 {
+		issues = projects.FilterByKind(issues, base.Kinds)
+	}
+	if len(base.Repositories) > 0 {
+		issues = projects.FilterByRepository(issues, base.Repositories)
+	}
+	tracker.CountCarryovers(issues)
+
+	for _, issue := range issues {
+		report.evaluate(issue, rule)
+	}
+
+	incompleteIssues := projects.FilterIncompleteIssues(issues, rule)
 
-	incompleteIssues := projects.FilterIncompleteIssues(issues, projectID)
-	
 	if len(incompleteIssues) == 0 {
-		fmt.Println("\n✅ No incomplete issues found in the previous iteration!")
-		return nil
+		ui.Printf("\n✅ No incomplete issues found in %s!\n", iterationTitles(sources))
+		if planning {
+			if err := writePlan(opts.PlanOut, base.ProjectURL, projectID, iterationInfo, sources, to, nil); err != nil {
+				return err
+			}
+		}
+		return base.WriteOutput(report)
 	}
 
-	ui.PrintIssueList(incompleteIssues, "📋 Incomplete issues found")
+	ui.PrintIssueList(incompleteIssues, "📋 Incomplete issues found", rule)
+
+	// Items at the carryover limit are only moved when confirmed by hand
+	candidates := incompleteIssues
+	var overLimit []*github.Issue
+	if opts.MaxCarryovers > 0 {
+		candidates = nil
+		for _, issue := range incompleteIssues {
+			if opts.overLimit(issue) {
+				overLimit = append(overLimit, issue)
+			}
+			if !opts.overLimit(issue) || (opts.OverMax == overMaxFlag && !base.Silent) {
+				candidates = append(candidates, issue)
+			}
+		}
+	}
+	if len(overLimit) > 0 {
+		decision, heading := decisionExcluded, "🚩 Carried over too often, not moving"
+		if opts.OverMax == overMaxFlag {
+			decision, heading = decisionFlagged, "🚩 Carried over too often, review before moving"
+			if base.Silent {
+				heading = "🚩 Carried over too often, not moving without review"
+			}
+		}
+		ui.PrintIssueList(overLimit, heading, rule)
+		for _, issue := range overLimit {
+			report.item(issue).Decision = decision
+		}
+	}
 
 	var issuesToMove []*github.Issue
-	prompter := ui.NewPrompter()
 
 	if base.Silent {
-		issuesToMove = incompleteIssues
-		fmt.Printf("\n🤖 Silent mode: All %d incomplete issues will be moved\n", len(issuesToMove))
+		issuesToMove = candidates
+		if len(candidates) < len(incompleteIssues) {
+			ui.Printf("\n🤖 Silent mode: %d of %d incomplete issues will be moved\n", len(candidates), len(incompleteIssues))
+		} else {
+			ui.Printf("\n🤖 Silent mode: All %d incomplete issues will be moved\n", len(candidates))
+		}
 	} else {
-		fmt.Println("\n🤔 Please review each issue:")
-		for _, issue := range incompleteIssues {
-			if prompter.ConfirmIssue(issue) {
+		ui.Println("\n🤔 Please review each issue:")
+		for _, issue := range candidates {
+			if opts.overLimit(issue) {
+				ui.Printf("\n🚩 %s has been carried over %d times (--max-carryovers %d)", issue.Ref(), issue.Carryovers, opts.MaxCarryovers)
+			}
+			decision := prompter.ConfirmIssue(ctx, issue, rule, to.Title)
+			if decision == ui.DecisionQuit {
+				// Quitting abandons the review, including items already accepted
+				issuesToMove = nil
+				break
+			}
+			if decision == ui.DecisionMove {
 				issuesToMove = append(issuesToMove, issue)
 			}
 		}
 	}
 
-	if !base.DryRun && len(issuesToMove) > 0 {
-		fmt.Println("\n🔄 Moving issues to current iteration...")
-		for i, issue := range issuesToMove {
+	for _, issue := range issuesToMove {
+		item := report.item(issue)
+		item.Decision = decisionMove
+		switch {
+		case planning:
+			item.Result = resultPlanned
+		case base.DryRun:
+			item.Result = resultDryRun
+		}
+	}
+
+	summary := ui.Summary{
+		Target:   to.Title,
+		Found:    len(incompleteIssues),
+		Selected: len(issuesToMove),
+		DryRun:   dryRun,
+	}
+
+	if planning {
+		if err := writePlan(opts.PlanOut, base.ProjectURL, projectID, iterationInfo, sources, to, issuesToMove); err != nil {
+			return err
+		}
+	}
+
+	if base.DryRun && !planning && audit.enabled() {
+		if n := len(annotatable(issuesToMove)); n > 0 {
+			ui.Printf("\n💬 Would add %s to %d issues and pull requests\n", audit.describe(), n)
+		}
+	}
+
+	// Items already moved still get their carryover counts and comments
+	// after an interrupt, so that they match the move
+	followUp := context.WithoutCancel(ctx)
+
+	var movedIssues []*github.Issue
+	if !dryRun && len(issuesToMove) > 0 {
+		ui.Printf("\n🔄 Moving issues to %s...\n", to.Title)
+		var updates []projects.ItemUpdate
+		var owners []*github.Issue
+		itemOwners := make(map[string]*github.Issue)
+		for _, issue := range issuesToMove {
 			for _, item := range issue.ProjectItems.Nodes {
-				err := manager.UpdateItemIteration(item.ID, iterationInfo.FieldID, iterationInfo.Current.ID)
-				if err != nil {
-					fmt.Printf("❌ Failed to move issue #%d: %v\n", issue.Number, err)
-				} else {
-					fmt.Printf("✅ Moved issue #%d (%d/%d)\n", issue.Number, i+1, len(issuesToMove))
+				updates = append(updates, projects.ItemUpdate{ItemID: item.ID, IterationID: to.ID})
+				owners = append(owners, issue)
+				itemOwners[item.ID] = issue
+			}
+		}
+
+		changes := journal.New(journal.CommandRollover,
+			journal.Project{URL: base.ProjectURL, ID: projectID},
+			journal.Field{ID: iterationInfo.FieldID, Name: iterationInfo.FieldName},
+			viewerLogin(ctx, client))
+		moves := make([]journal.Entry, len(updates))
+		for i, update := range updates {
+			issue := owners[i]
+			moves[i] = journal.Entry{
+				ItemID:          update.ItemID,
+				Ref:             issue.Ref(),
+				Title:           issue.Title,
+				FromIterationID: issue.IterationID,
+				FromIteration:   issue.IterationTitle,
+				ToIterationID:   to.ID,
+				ToIteration:     to.Title,
+			}
+		}
+		progress, err := startJournal(changes, moves, opts.Journal)
+		if err != nil {
+			return err
+		}
+
+		results := manager.UpdateItemIterations(ctx, iterationInfo.FieldID, updates, opts.BatchSize, progress.settle)
+		for i, res := range results {
+			issue := owners[i]
+			result := report.item(issue)
+			switch {
+			case errors.Is(res.Err, projects.ErrNotAttempted):
+				summary.NotAttempted++
+				result.Result = resultNotAttempted
+			case res.Err != nil:
+				ui.Printf("❌ Failed to move %s: %v\n", issue.Ref(), res.Err)
+				summary.Failed++
+				result.Result = resultFailed
+				result.Error = res.Err.Error()
+			default:
+				summary.Moved++
+				ui.Printf("✅ Moved %s (%d/%d)\n", issue.Ref(), i+1, len(results))
+				result.Result = resultMoved
+				movedIssues = append(movedIssues, issue)
+			}
+		}
+
+		if tracker.Enabled() && len(movedIssues) > 0 {
+			previous := make(map[*github.Issue]int, len(movedIssues))
+			for _, issue := range movedIssues {
+				previous[issue] = issue.Carryovers
+			}
+			if failed := incrementCarryovers(followUp, client, manager, carryoverField, movedIssues); failed > 0 {
+				ui.Printf("⚠️  %d of %d moved items still show their old carryover count\n", failed, len(movedIssues))
+			}
+			for _, issue := range movedIssues {
+				report.item(issue).Carryovers = issue.Carryovers
+			}
+
+			// Journal the new counts so undo can put them back. A label
+			// belongs to the issue, so it is recorded against one item only.
+			labelled := make(map[*github.Issue]bool)
+			for i := range changes.Entries {
+				entry := &changes.Entries[i]
+				issue := itemOwners[entry.ItemID]
+				if issue == nil || !entry.Applied() || (carryoverField == nil && labelled[issue]) {
+					continue
 				}
+				entry.Carryover = carryoverChange(issue, carryoverField, previous[issue])
+				labelled[issue] = entry.Carryover != nil
+			}
+		}
+
+		report.Journal = progress.finish()
+	}
+
+	if annotate := annotatable(movedIssues); audit.enabled() && len(annotate) > 0 {
+		ui.Printf("\n💬 Adding %s to %d issues and pull requests...\n", audit.describe(), len(annotate))
+		writer, err := client.Issues()
+		failed := len(annotate)
+		if err != nil {
+			ui.Printf("⚠️  Warning: %v\n", err)
+		} else {
+			failed = audit.apply(followUp, writer, annotate, to.Title, base.ProjectURL)
+		}
+		if failed > 0 {
+			ui.Printf("⚠️  %d of %d moved items are missing their comment or labels\n", failed, len(annotate))
+		}
+	}
+
+	summary.Interrupted = ctx.Err() != nil
+	prompter.ShowSummary(summary)
+
+	if summary.Moved > 0 && report.Journal != "" {
+		ui.Printf("↩️  To move these items back, run: gh-projects iteration undo %s\n", report.Journal)
+	}
+	if limit, ok := client.RateLimit(); ok {
+		ui.Printf("📊 GitHub API budget: %d of %d requests left, resets at %s\n", limit.Remaining, limit.Limit, limit.Reset.Format("15:04"))
+	}
+
+	if err := base.WriteOutput(report); err != nil {
+		return err
+	}
+	if summary.Interrupted {
+		return fmt.Errorf("rollover stopped before it finished: %w", context.Cause(ctx))
+	}
+	return nil
+}
+
+// resolveRolloverIterations resolves the source iterations and the --to
+// iteration and checks that they describe a sensible move before anything is
+// changed.
+func resolveRolloverIterations(info *projects.IterationInfo, opts *rolloverOptions) (sources []*github.Iteration, to *github.Iteration, err error) {
+	to, err = info.Resolve(opts.To)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid --to iteration: %w", err)
+	}
+	if to.Completed {
+		ui.Printf("⚠️  Target iteration %s has already been completed\n", to.Title)
+	}
+
+	if opts.AllPast || opts.Since != "" {
+		var since time.Time
+		if opts.Since != "" {
+			since, err = info.ResolveStart(opts.Since)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid --since: %w", err)
 			}
 		}
+
+		sources = info.Past(since, to, time.Now())
+		if len(sources) == 0 {
+			return nil, nil, fmt.Errorf("no completed iterations found before %s in the requested range", to.Title)
+		}
+		return sources, to, nil
+	}
+
+	from, err := info.Resolve(opts.From)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid --from iteration: %w", err)
 	}
 
-	prompter.ShowSummary(len(incompleteIssues), len(issuesToMove), base.DryRun)
+	if from.ID == to.ID {
+		return nil, nil, fmt.Errorf("--from and --to both resolve to %s; nothing to roll over", from.Title)
+	}
+	if to.StartDate.Before(from.StartDate) {
+		return nil, nil, fmt.Errorf("--to iteration %s starts before --from iteration %s; refusing to move items backwards", to.Title, from.Title)
+	}
 
+	return []*github.Iteration{from}, to, nil
+}
+
+// writePlan writes a plan moving issues to the iteration to, for apply.
+func writePlan(path, projectURL, projectID string, info *projects.IterationInfo, sources []*github.Iteration, to *github.Iteration, issues []*github.Issue) error {
+	p := &plan.Plan{
+		Version:   plan.Version,
+		CreatedAt: time.Now().UTC(),
+		Project:   plan.Project{URL: projectURL, ID: projectID},
+		Field:     plan.Field{ID: info.FieldID, Name: info.FieldName},
+		To:        plan.Iteration{ID: to.ID, Title: to.Title},
+		Items:     []plan.Item{},
+	}
+	for _, source := range sources {
+		p.From = append(p.From, plan.Iteration{ID: source.ID, Title: source.Title})
+	}
+	for _, issue := range issues {
+		for _, item := range issue.ProjectItems.Nodes {
+			p.Items = append(p.Items, plan.Item{
+				ItemID:          item.ID,
+				Ref:             issue.Ref(),
+				Title:           issue.Title,
+				FromIterationID: issue.IterationID,
+				FromIteration:   issue.IterationTitle,
+				UpdatedAt:       item.UpdatedAt,
+			})
+		}
+	}
+
+	if err := p.Save(path); err != nil {
+		return err
+	}
+	ui.Printf("\n📝 Plan moving %d items to %s written to %s\n", len(p.Items), to.Title, path)
+	ui.Printf("   Review it, then apply it with: gh-projects iteration apply %s\n", path)
 	return nil
-}
\ No newline at end of file
+}
+
+// sortBySource orders issues by the position of their source iteration in
+// iterationIDs, keeping the fetch order within an iteration.
+func sortBySource(issues []*github.Issue, iterationIDs []string) {
+	position := make(map[string]int, len(iterationIDs))
+	for i, id := range iterationIDs {
+		position[id] = i
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return position[issues[i].IterationID] < position[issues[j].IterationID]
+	})
+}
+
+func iterationTitles(iterations []*github.Iteration) string {
+	titles := make([]string, len(iterations))
+	for i, iteration := range iterations {
+		titles[i] = iteration.Title
+	}
+	return strings.Join(titles, ", ")
+}
@@ -0,0 +1,491 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/output"
+)
+
+const testProjectID = "PVT_test"
+
+// filterRejected is GitHub refusing an items filter, which makes the fixtures
+// scripted with addItems scan the whole project.
+const filterRejected = `{"errors":[{"message":"Field 'items' doesn't accept argument 'query'"}]}`
+
+// TestMain keeps the journals written by rollovers under test out of the
+// user's state directory.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gh-projects-state")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("XDG_STATE_HOME", dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func day(n int) string {
+	return time.Now().AddDate(0, 0, n).Format("2006-01-02")
+}
+
+// addProject scripts the lookup of project 7 of the acme organization, whose
+// fields are fieldsJSON, a JSON array of field nodes.
+func addProject(fake *github.FakeTransport, fieldsJSON string) {
+	fake.Add(github.GetProjectQuery, map[string]interface{}{"owner": "acme", "number": 7},
+		`{"data":{"user":null,"organization":{"projectV2":{"id":"PVT_test","title":"Roadmap","number":7}}},
+		"errors":[{"type":"NOT_FOUND","path":["user"],"message":"Could not resolve to a User with the login of 'acme'."}]}`)
+	fake.Add(github.GetProjectFieldsQuery, map[string]interface{}{"projectId": testProjectID},
+		`{"data":{"node":{"fields":{"nodes":`+fieldsJSON+`}}}}`)
+}
+
+// addItems scripts the items of the project, nodesJSON, a JSON array of item
+// nodes on a single page. GitHub rejects the items filter, so they are found
+// by scanning the whole project.
+func addItems(fake *github.FakeTransport, nodesJSON string) {
+	fake.AddAny(github.GetFilteredIterationItemsQuery, filterRejected)
+	fake.Add(github.GetIterationItemsQuery, map[string]interface{}{"projectId": testProjectID},
+		`{"data":{"node":{"items":{"pageInfo":{"hasNextPage":false,"endCursor":"c1"},"nodes":`+nodesJSON+`}}}}`)
+}
+
+// newRolloverFake scripts a project with a finished "Sprint 1" holding an
+// open issue in acme/web, a done issue, a closed issue, a merged pull request
+// and a draft issue, the first two assigned to octocat, a current "Sprint 2",
+// and an older "Sprint 0" with one open issue.
+func newRolloverFake() *github.FakeTransport {
+	fake := github.NewFakeTransport()
+	addProject(fake, fmt.Sprintf(`[
+		{"id":"F_iter","name":"Sprint","dataType":"ITERATION","configuration":{
+			"iterations":[{"id":"it-2","title":"Sprint 2","startDate":%q,"duration":14}],
+			"completedIterations":[
+				{"id":"it-0","title":"Sprint 0","startDate":%q,"duration":14},
+				{"id":"it-1","title":"Sprint 1","startDate":%q,"duration":14}
+			]
+		}}
+	]`, day(-3), day(-31), day(-17)))
+	addItems(fake, `[
+			{"id":"PVTI_open","updatedAt":"2026-10-01T09:00:00Z","content":{"__typename":"Issue","id":"I_1","number":1,"title":"Open","state":"OPEN","repository":{"name":"web","owner":{"login":"acme"}},"assignees":{"nodes":[{"login":"octocat"}]}},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":"In Progress"}
+			]}},
+			{"id":"PVTI_done","content":{"__typename":"Issue","id":"I_2","number":2,"title":"Done","state":"OPEN","assignees":{"nodes":[{"login":"octocat"},{"login":"hubot"}]}},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":"Done"}
+			]}},
+			{"id":"PVTI_closed","content":{"__typename":"Issue","id":"I_3","number":3,"title":"Closed","state":"CLOSED"},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"}
+			]}},
+			{"id":"PVTI_pr","content":{"__typename":"PullRequest","id":"PR_5","number":5,"title":"Merged","state":"MERGED","merged":true},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"}
+			]}},
+			{"id":"PVTI_draft","updatedAt":"2026-10-02T09:00:00Z","content":{"__typename":"DraftIssue","id":"DI_6","title":"Draft"},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"},
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":"Todo"}
+			]}},
+			{"id":"PVTI_old","content":{"__typename":"Issue","id":"I_7","number":7,"title":"Stranded","state":"OPEN"},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-0","title":"Sprint 0"}
+			]}},
+			{"id":"PVTI_current","content":{"__typename":"Issue","id":"I_4","number":4,"title":"Current","state":"OPEN"},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"}
+			]}}
+	]`)
+	fake.Add(github.UpdateItemIterationMutation, map[string]interface{}{
+		"projectId":   testProjectID,
+		"itemId":      "PVTI_open",
+		"fieldId":     "F_iter",
+		"iterationId": "it-2",
+	}, `{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_open"}}}}`)
+	fake.Add(github.UpdateItemIterationMutation, map[string]interface{}{
+		"projectId":   testProjectID,
+		"itemId":      "PVTI_draft",
+		"fieldId":     "F_iter",
+		"iterationId": "it-2",
+	}, `{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_draft"}}}}`)
+	fake.Add(github.UpdateItemIterationMutation, map[string]interface{}{
+		"projectId":   testProjectID,
+		"itemId":      "PVTI_old",
+		"fieldId":     "F_iter",
+		"iterationId": "it-2",
+	}, `{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_old"}}}}`)
+	addBatchMove(fake, "it-2", "PVTI_open", "PVTI_draft")
+	addBatchMove(fake, "it-2", "PVTI_old", "PVTI_open", "PVTI_draft")
+	return fake
+}
+
+// batchVariables returns the variables of a batched mutation moving itemIDs
+// to iterationID.
+func batchVariables(iterationID string, itemIDs ...string) map[string]interface{} {
+	variables := map[string]interface{}{"projectId": testProjectID, "fieldId": "F_iter"}
+	for i, id := range itemIDs {
+		variables[fmt.Sprintf("item%d", i)] = id
+		variables[fmt.Sprintf("iteration%d", i)] = iterationID
+	}
+	return variables
+}
+
+// addBatchMove scripts a successful batched mutation moving itemIDs to
+// iterationID.
+func addBatchMove(fake *github.FakeTransport, iterationID string, itemIDs ...string) {
+	aliases := make([]string, len(itemIDs))
+	for i, id := range itemIDs {
+		aliases[i] = fmt.Sprintf(`"u%d":{"projectV2Item":{"id":%q}}`, i, id)
+	}
+	fake.Add(github.UpdateItemIterationsMutation(len(itemIDs)), batchVariables(iterationID, itemIDs...),
+		`{"data":{`+strings.Join(aliases, ",")+`}}`)
+}
+
+// movedItems returns the IDs of the items sent in iteration mutations,
+// single or batched, in the order they were sent.
+func movedItems(fake *github.FakeTransport) []string {
+	var moved []string
+	for _, call := range fake.Calls() {
+		if !strings.Contains(call.Query, "updateProjectV2ItemFieldValue") || !strings.Contains(call.Query, "iterationId:") {
+			continue
+		}
+		if id, ok := call.Variables["itemId"].(string); ok {
+			moved = append(moved, id)
+			continue
+		}
+		for i := 0; ; i++ {
+			id, ok := call.Variables[fmt.Sprintf("item%d", i)].(string)
+			if !ok {
+				break
+			}
+			moved = append(moved, id)
+		}
+	}
+	return moved
+}
+
+func TestIterationRolloverSilent(t *testing.T) {
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/7",
+		Silent:     true,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+
+	if got := len(fake.CallsFor(github.UpdateItemIterationMutation)); got != 0 {
+		t.Errorf("sent %d single mutations, want them batched", got)
+	}
+	batches := fake.CallsFor(github.UpdateItemIterationsMutation(2))
+	if len(batches) != 1 {
+		t.Fatalf("got %d batched mutations, want 1", len(batches))
+	}
+	if got, want := batches[0].Variables, batchVariables("it-2", "PVTI_open", "PVTI_draft"); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("batched mutation variables = %v, want %v", got, want)
+	}
+}
+
+func TestIterationRolloverUnbatched(t *testing.T) {
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", BatchSize: 1, BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/7",
+		Silent:     true,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+
+	mutations := fake.CallsFor(github.UpdateItemIterationMutation)
+	if len(mutations) != 2 {
+		t.Fatalf("got %d mutations, want 2", len(mutations))
+	}
+	for i, want := range []string{"PVTI_open", "PVTI_draft"} {
+		if got := mutations[i].Variables["itemId"]; got != want {
+			t.Errorf("mutation %d moved item %v, want %s", i, got, want)
+		}
+		if got := mutations[i].Variables["iterationId"]; got != "it-2" {
+			t.Errorf("mutation %d moved to iteration %v, want it-2", i, got)
+		}
+	}
+}
+
+func TestIterationRolloverBatchPartialFailure(t *testing.T) {
+	fake := newRolloverFake()
+	fake.Add(github.UpdateItemIterationsMutation(2), batchVariables("it-2", "PVTI_old", "PVTI_open"),
+		`{"data":{"u0":{"projectV2Item":{"id":"PVTI_old"}},"u1":null},"errors":[{"type":"FORBIDDEN","path":["u1"],"message":"Resource not accessible"}]}`)
+
+	var stdout bytes.Buffer
+	opts := &rolloverOptions{To: "current", AllPast: true, BatchSize: 2, BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/7",
+		Silent:     true,
+		Output:     output.JSON,
+		stdout:     &stdout,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+
+	var report rolloverReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+	}
+
+	got := make(map[string]string)
+	for _, item := range report.Items {
+		if item.Decision == decisionMove {
+			got[item.ItemID] = item.Result + ":" + item.Error
+		}
+	}
+	want := map[string]string{
+		"PVTI_old":   "moved:",
+		"PVTI_open":  "failed:failed to update item iteration: Resource not accessible (FORBIDDEN)",
+		"PVTI_draft": "moved:",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("item results = %v, want %v", got, want)
+	}
+}
+
+func TestIterationRolloverAllPast(t *testing.T) {
+	for _, opts := range []*rolloverOptions{
+		{To: "current", AllPast: true},
+		{To: "current", Since: "Sprint 0"},
+		{To: "current", Since: day(-40)},
+	} {
+		fake := newRolloverFake()
+		opts.BaseCommand = &BaseCommand{
+			ProjectURL: "https://github.com/orgs/acme/projects/7",
+			Silent:     true,
+			transport:  fake,
+		}
+
+		if err := runIterationRollover(context.Background(), opts); err != nil {
+			t.Fatalf("rollover: %v", err)
+		}
+
+		moved := movedItems(fake)
+		want := []string{"PVTI_old", "PVTI_open", "PVTI_draft"}
+		if fmt.Sprint(moved) != fmt.Sprint(want) {
+			t.Errorf("since %q: moved %v, want %v", opts.Since, moved, want)
+		}
+	}
+}
+
+func TestIterationRolloverIncludeTypes(t *testing.T) {
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/7",
+		Silent:     true,
+		Kinds:      []github.ItemKind{github.KindIssue},
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+
+	mutations := fake.CallsFor(github.UpdateItemIterationMutation)
+	if len(mutations) != 1 || mutations[0].Variables["itemId"] != "PVTI_open" {
+		t.Fatalf("got mutations %v, want only PVTI_open", mutations)
+	}
+}
+
+func TestIterationRolloverDryRun(t *testing.T) {
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/7",
+		Silent:     true,
+		DryRun:     true,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+
+	if got := movedItems(fake); len(got) != 0 {
+		t.Errorf("dry run moved %v, want nothing", got)
+	}
+}
+
+func TestIterationRolloverValidatesIterations(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+	}{
+		{name: "same iteration", from: "Sprint 2", to: "current"},
+		{name: "backwards", from: "current", to: "@-1"},
+		{name: "unknown", from: "Sprint 9", to: "current"},
+		{name: "no next", from: "previous", to: "next"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newRolloverFake()
+			opts := &rolloverOptions{From: tt.from, To: tt.to, BaseCommand: &BaseCommand{
+				ProjectURL: "https://github.com/orgs/acme/projects/7",
+				Silent:     true,
+				transport:  fake,
+			}}
+
+			if err := runIterationRollover(context.Background(), opts); err == nil {
+				t.Fatal("rollover succeeded, want a validation error")
+			}
+			if got := movedItems(fake); len(got) != 0 {
+				t.Errorf("moved %v, want nothing", got)
+			}
+		})
+	}
+}
+
+func TestIterationRolloverAmbiguousField(t *testing.T) {
+	fake := github.NewFakeTransport()
+	addProject(fake, `[
+		{"id":"F_sprint","name":"Sprint","dataType":"ITERATION","configuration":{"iterations":[]}},
+		{"id":"F_train","name":"Release Train","dataType":"ITERATION","configuration":{"iterations":[]}}
+	]`)
+
+	opts := &rolloverOptions{From: "previous", To: "current", BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/7",
+		Silent:     true,
+		transport:  fake,
+	}}
+
+	err := runIterationRollover(context.Background(), opts)
+	if err == nil || !strings.Contains(err.Error(), "--field") || !strings.Contains(err.Error(), "Release Train") {
+		t.Fatalf("rollover error = %v, want it to list the iteration fields", err)
+	}
+}
+
+func TestIterationRolloverJSONOutput(t *testing.T) {
+	fake := newRolloverFake()
+	var stdout bytes.Buffer
+	opts := &rolloverOptions{From: "previous", To: "current", BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/7",
+		Silent:     true,
+		Output:     output.JSON,
+		stdout:     &stdout,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+
+	var report rolloverReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+	}
+
+	if report.Project.ID != testProjectID || report.To.ID != "it-2" || len(report.From) != 1 || report.From[0].ID != "it-1" {
+		t.Errorf("unexpected project or iterations: %+v", report)
+	}
+
+	got := make(map[string]string)
+	for _, item := range report.Items {
+		got[item.ItemID] = item.Decision + "/" + item.Result
+	}
+	want := map[string]string{
+		"PVTI_open":   "move/moved",
+		"PVTI_done":   "done/",
+		"PVTI_closed": "done/",
+		"PVTI_pr":     "done/",
+		"PVTI_draft":  "move/moved",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("item decisions = %v, want %v", got, want)
+	}
+}
+
+// interruptingTransport cancels the run once the first mutation has been
+// answered, as Ctrl-C would while the rollover is moving items.
+type interruptingTransport struct {
+	github.Transport
+	cancel context.CancelFunc
+}
+
+func (t *interruptingTransport) Execute(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
+	body, err := t.Transport.Execute(ctx, query, variables)
+	if strings.Contains(query, "updateProjectV2ItemFieldValue") {
+		t.cancel()
+	}
+	return body, err
+}
+
+func TestIterationRolloverInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := newRolloverFake()
+	var stdout bytes.Buffer
+	opts := &rolloverOptions{To: "current", AllPast: true, BatchSize: 1, BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/7",
+		Silent:     true,
+		Output:     output.JSON,
+		stdout:     &stdout,
+		transport:  &interruptingTransport{Transport: fake, cancel: cancel},
+	}}
+
+	err := runIterationRollover(ctx, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("rollover error = %v, want it to report the interrupt", err)
+	}
+
+	if got := movedItems(fake); fmt.Sprint(got) != "[PVTI_old]" {
+		t.Errorf("moved %v, want only the item in flight when interrupted", got)
+	}
+
+	var report rolloverReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+	}
+	got := make(map[string]string)
+	for _, item := range report.Items {
+		if item.Decision == decisionMove {
+			got[item.ItemID] = item.Result
+		}
+	}
+	want := map[string]string{
+		"PVTI_old":   resultMoved,
+		"PVTI_open":  resultNotAttempted,
+		"PVTI_draft": resultNotAttempted,
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("item results = %v, want %v", got, want)
+	}
+
+	// The moves never sent were journaled as pending and dropped again
+	recorded, err := journal.Load(report.Journal)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(recorded.Entries) != 1 || recorded.Entries[0].ItemID != "PVTI_old" || !recorded.Entries[0].Applied() {
+		t.Errorf("journal entries = %+v, want only PVTI_old, applied", recorded.Entries)
+	}
+}
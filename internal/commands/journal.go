@@ -0,0 +1,114 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+)
+
+// viewerLogin returns the login of the authenticated user, recorded in
+// journals as the actor, or "" when it cannot be looked up.
+func viewerLogin(ctx context.Context, client *github.Client) string {
+	result, err := github.GraphQLTyped[github.ViewerResponse](ctx, client, github.GetViewerQuery, nil)
+	if err != nil {
+		log.Printf("Could not look up the authenticated user for the journal: %v", err)
+		return ""
+	}
+	if result.Viewer == nil {
+		return ""
+	}
+	return result.Viewer.Login
+}
+
+// journalProgress keeps the journal of a command on disk while its moves are
+// made. The moves are written as pending before the first one is sent and
+// settled batch by batch, so a run that is killed still leaves a journal undo
+// can work from.
+type journalProgress struct {
+	journal *journal.Journal
+	path    string
+	settled int
+}
+
+// startJournal records moves in j as pending and writes j to path, or to the
+// journal directory when path is empty. Nothing has been changed yet, so a
+// journal that cannot be written is an error rather than a warning.
+func startJournal(j *journal.Journal, moves []journal.Entry, path string) (*journalProgress, error) {
+	for _, move := range moves {
+		move.Pending = true
+		j.Record(move)
+	}
+	written, err := j.Save(path)
+	if err != nil {
+		return nil, fmt.Errorf("nothing was changed: %w", err)
+	}
+	return &journalProgress{journal: j, path: written}, nil
+}
+
+// settle records results as the outcome of the next pending moves, in order,
+// and rewrites the journal. Moves that were never sent stay pending.
+func (p *journalProgress) settle(results []projects.ItemUpdateResult) {
+	for _, res := range results {
+		entry := &p.journal.Entries[p.settled]
+		p.settled++
+		if errors.Is(res.Err, projects.ErrNotAttempted) {
+			continue
+		}
+		entry.Pending = false
+		entry.Time = time.Now().UTC()
+		if res.Err != nil {
+			entry.Error = res.Err.Error()
+		}
+	}
+	if _, err := p.journal.Save(p.path); err != nil {
+		ui.Printf("⚠️  Warning: %v\n", err)
+	}
+}
+
+// finish drops the moves that were never sent and writes the journal a last
+// time. It returns where the journal is, or "" when it records neither a move
+// that was sent nor a skipped item and has been removed.
+func (p *journalProgress) finish() string {
+	entries := p.journal.Entries[:0]
+	for _, entry := range p.journal.Entries {
+		if !entry.Pending {
+			entries = append(entries, entry)
+		}
+	}
+	p.journal.Entries = entries
+
+	if len(entries) == 0 && len(p.journal.Skipped) == 0 {
+		if err := os.Remove(p.path); err != nil {
+			log.Printf("Could not remove the empty journal %s: %v", p.path, err)
+		}
+		return ""
+	}
+	if _, err := p.journal.Save(p.path); err != nil {
+		ui.Printf("⚠️  Warning: %v; %s may still list finished moves as pending\n", err, p.path)
+		return p.path
+	}
+	ui.Printf("📝 Journal written to %s\n", p.path)
+	return p.path
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kriscoleman/gh-projects/internal/config"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/output"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// reportOptions holds the flags specific to iteration report.
+type reportOptions struct {
+	*BaseCommand
+	Iteration string
+}
+
+func NewIterationReportCmd(cfg *config.Config) *cobra.Command {
+	opts := &reportOptions{BaseCommand: &BaseCommand{config: cfg}}
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarize how an iteration went",
+		Long: `Summarize the items in an iteration: how many were completed, broken down
+by status, repository and assignee; items whose open or closed state
+disagrees with their status; and the items rolled over into and out of it.
+
+Carryovers are read from the journals in the journal directory, so only moves
+made by this tool, and not undone since, are counted.
+
+Use -o markdown for tables to paste into a retro, or -o json for the full
+report.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(cmd); err != nil {
+				return err
+			}
+			ctx, cancel := opts.Context(cmd.Context())
+			defer cancel()
+			return runIterationReport(ctx, opts)
+		},
+	}
+
+	opts.AddCommonFlags(cmd)
+	opts.AddCompletionFlags(cmd)
+	opts.AddFieldFlag(cmd)
+	opts.AddFilterFlags(cmd)
+	opts.RequireProject()
+	cmd.Flags().StringVar(&opts.Iteration, "iteration", "current", "Iteration to report on, e.g. previous, @-2 or a title")
+
+	return cmd
+}
+
+func runIterationReport(ctx context.Context, opts *reportOptions) error {
+	base := opts.BaseCommand
+	base.RouteOutput()
+
+	ui.Println("📊 GitHub Projects - Iteration Report")
+	ui.Println("=====================================")
+
+	client, err := base.GetGitHubClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize GitHub client: %w", err)
+	}
+
+	owner, number, projectID, err := base.ParseProjectURL(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	ui.Printf("📂 Project: %s/%d\n", owner, number)
+
+	manager := base.NewManager(client, projectID)
+
+	iterationInfo, err := base.GetIterations(ctx, manager, ui.NewPrompter())
+	if err != nil {
+		return err
+	}
+
+	iteration, err := iterationInfo.Resolve(opts.Iteration)
+	if err != nil {
+		return fmt.Errorf("invalid --iteration: %w", err)
+	}
+
+	rule := base.Completion
+	if rule == nil {
+		rule = projects.DefaultCompletionRule()
+	}
+
+	ui.Printf("\n🔍 Fetching issues from %s...\n", iteration.Title)
+	issues, err := manager.GetIterationItems(ctx, iteration)
+	if err != nil {
+		return fmt.Errorf("failed to fetch issues: %w", err)
+	}
+	if len(base.Kinds) > 0 {
+		issues = projects.FilterByKind(issues, base.Kinds)
+	}
+	if len(base.Repositories) > 0 {
+		issues = projects.FilterByRepository(issues, base.Repositories)
+	}
+
+	// Without journals the report simply has no carryovers
+	var journals []*journal.Journal
+	if dir, err := journal.Dir(); err != nil {
+		ui.Printf("⚠️  Warning: carryovers are not reported: %v\n", err)
+	} else if journals, err = journal.Standing(dir, base.ProjectURL); err != nil {
+		ui.Printf("⚠️  Warning: carryovers are not reported: %v\n", err)
+	}
+
+	report := newSprintReport(projectSummary{
+		URL:    base.ProjectURL,
+		Owner:  owner,
+		Number: number,
+		ID:     projectID,
+	}, iterationInfo, iteration, issues, rule, journals)
+
+	if base.Output == output.Text {
+		printSprintReport(report)
+	}
+	return base.WriteOutput(report)
+}
+
+// printSprintReport prints the report as tables in the terminal.
+func printSprintReport(report *sprintReport) {
+	ui.Printf("\n🗓️  %s (from %s, %d days)\n", report.Iteration.Title, report.Iteration.StartDate, report.Iteration.Duration)
+	ui.Println(strings.Repeat("=", 50))
+	ui.Printf("Completed: %d of %d (%s)\n", report.Completed, report.Total, report.percentComplete())
+	ui.Printf("Incomplete: %d\n", report.Incomplete)
+
+	for _, section := range report.sections() {
+		ui.Printf("\n%s\n", section.Title)
+		ui.Println(strings.Repeat("-", 50))
+		if len(section.Rows) == 0 {
+			ui.Println(section.Empty)
+			continue
+		}
+		ui.PrintTable(section.Header, section.Rows)
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/output"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+)
+
+// Group names for items without a repository or an assignee.
+const (
+	noRepository = "No repository"
+	unassigned   = "Unassigned"
+)
+
+// sprintReport is the document iteration report writes with --output.
+type sprintReport struct {
+	Project    projectSummary   `json:"project" yaml:"project"`
+	Field      string           `json:"field" yaml:"field"`
+	Iteration  iterationSummary `json:"iteration" yaml:"iteration"`
+	Total      int              `json:"total" yaml:"total"`
+	Completed  int              `json:"completed" yaml:"completed"`
+	Incomplete int              `json:"incomplete" yaml:"incomplete"`
+
+	ByStatus     []*reportGroup `json:"byStatus" yaml:"byStatus"`
+	ByRepository []*reportGroup `json:"byRepository" yaml:"byRepository"`
+	ByAssignee   []*reportGroup `json:"byAssignee" yaml:"byAssignee"`
+
+	// Mismatches are items whose state and status disagree.
+	Mismatches []reportMismatch `json:"mismatches" yaml:"mismatches"`
+	// CarriedIn and CarriedOut are the moves into and out of the iteration
+	// recorded in the journals that still stand.
+	CarriedIn  []reportCarryover `json:"carriedIn" yaml:"carriedIn"`
+	CarriedOut []reportCarryover `json:"carriedOut" yaml:"carriedOut"`
+
+	Items []*reportItem `json:"items" yaml:"items"`
+}
+
+// reportGroup counts the items sharing a status, repository or assignee.
+type reportGroup struct {
+	Name       string `json:"name" yaml:"name"`
+	Completed  int    `json:"completed" yaml:"completed"`
+	Incomplete int    `json:"incomplete" yaml:"incomplete"`
+	Total      int    `json:"total" yaml:"total"`
+}
+
+// reportMismatch is an item closed with a status that isn't done, or open
+// with a status that is.
+type reportMismatch struct {
+	ItemID     string `json:"itemId" yaml:"itemId"`
+	Ref        string `json:"ref" yaml:"ref"`
+	Title      string `json:"title" yaml:"title"`
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	State      string `json:"state" yaml:"state"`
+	Status     string `json:"status" yaml:"status"`
+	Problem    string `json:"problem" yaml:"problem"`
+}
+
+// reportCarryover is an item moved into or out of the iteration. Iteration
+// is the other end of the move.
+type reportCarryover struct {
+	ItemID    string `json:"itemId" yaml:"itemId"`
+	Ref       string `json:"ref,omitempty" yaml:"ref,omitempty"`
+	Title     string `json:"title,omitempty" yaml:"title,omitempty"`
+	Iteration string `json:"iteration" yaml:"iteration"`
+	Time      string `json:"time" yaml:"time"`
+}
+
+// reportItem is an item in the iteration.
+type reportItem struct {
+	ItemID     string   `json:"itemId" yaml:"itemId"`
+	Type       string   `json:"type" yaml:"type"`
+	Number     int      `json:"number,omitempty" yaml:"number,omitempty"`
+	Title      string   `json:"title" yaml:"title"`
+	Repository string   `json:"repository,omitempty" yaml:"repository,omitempty"`
+	Assignees  []string `json:"assignees,omitempty" yaml:"assignees,omitempty"`
+	State      string   `json:"state,omitempty" yaml:"state,omitempty"`
+	Status     string   `json:"status" yaml:"status"`
+	Done       bool     `json:"done" yaml:"done"`
+}
+
+// newSprintReport summarizes issues, the items in iteration, under rule.
+// journals are searched for moves into and out of the iteration.
+func newSprintReport(project projectSummary, info *projects.IterationInfo, iteration *github.Iteration, issues []*github.Issue, rule *projects.CompletionRule, journals []*journal.Journal) *sprintReport {
+	report := &sprintReport{
+		Project:    project,
+		Field:      info.FieldName,
+		Iteration:  summarizeIteration(iteration),
+		Mismatches: []reportMismatch{},
+		CarriedIn:  []reportCarryover{},
+		CarriedOut: []reportCarryover{},
+		Items:      []*reportItem{},
+	}
+
+	byStatus := make(map[string]*reportGroup)
+	byRepository := make(map[string]*reportGroup)
+	byAssignee := make(map[string]*reportGroup)
+
+	for _, issue := range issues {
+		item := &reportItem{
+			Type:   projects.KindName(issue.Kind),
+			Number: issue.Number,
+			Title:  issue.Title,
+			State:  issue.State,
+			Status: projects.GetIssueStatus(issue, rule),
+			Done:   rule.IsDone(issue),
+		}
+		if len(issue.ProjectItems.Nodes) > 0 {
+			item.ItemID = issue.ProjectItems.Nodes[0].ID
+		}
+		if issue.Repository.Name != "" {
+			item.Repository = issue.Repository.Owner.Login + "/" + issue.Repository.Name
+		}
+		for _, assignee := range issue.Assignees.Nodes {
+			item.Assignees = append(item.Assignees, assignee.Login)
+		}
+		report.Items = append(report.Items, item)
+
+		report.Total++
+		if item.Done {
+			report.Completed++
+		} else {
+			report.Incomplete++
+		}
+
+		count(byStatus, item.Status, item.Done)
+		repository := item.Repository
+		if repository == "" {
+			repository = noRepository
+		}
+		count(byRepository, repository, item.Done)
+		if len(item.Assignees) == 0 {
+			count(byAssignee, unassigned, item.Done)
+		}
+		for _, assignee := range item.Assignees {
+			count(byAssignee, assignee, item.Done)
+		}
+
+		if problem := mismatch(issue, rule); problem != "" {
+			report.Mismatches = append(report.Mismatches, reportMismatch{
+				ItemID:     item.ItemID,
+				Ref:        issue.Ref(),
+				Title:      issue.Title,
+				Repository: item.Repository,
+				State:      issue.State,
+				Status:     item.Status,
+				Problem:    problem,
+			})
+		}
+	}
+
+	report.ByStatus = sortGroups(byStatus)
+	report.ByRepository = sortGroups(byRepository)
+	report.ByAssignee = sortGroups(byAssignee)
+	report.carryovers(info, iteration, journals)
+	return report
+}
+
+// count adds an item to the group called name in groups.
+func count(groups map[string]*reportGroup, name string, done bool) {
+	group, ok := groups[name]
+	if !ok {
+		group = &reportGroup{Name: name}
+		groups[name] = group
+	}
+	group.Total++
+	if done {
+		group.Completed++
+	} else {
+		group.Incomplete++
+	}
+}
+
+// sortGroups returns groups largest first, then by name.
+func sortGroups(groups map[string]*reportGroup) []*reportGroup {
+	sorted := make([]*reportGroup, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Total != sorted[j].Total {
+			return sorted[i].Total > sorted[j].Total
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// mismatch describes how the state and status of issue disagree, or returns
+// "" when they don't. Draft issues and items without a status have nothing
+// to disagree about.
+func mismatch(issue *github.Issue, rule *projects.CompletionRule) string {
+	if issue.State == "" || !rule.HasStatus(issue) {
+		return ""
+	}
+
+	closed := issue.State == "CLOSED" || issue.State == "MERGED"
+	switch done := rule.StatusIsDone(issue); {
+	case closed && !done:
+		return fmt.Sprintf("%s but not marked done", strings.ToLower(issue.State))
+	case !closed && done:
+		return "marked done but still open"
+	}
+	return ""
+}
+
+// carryovers fills in the moves into and out of iteration recorded in
+// journals for the same iteration field. When an item moved more than once
+// in the same direction, its latest move is kept.
+func (r *sprintReport) carryovers(info *projects.IterationInfo, iteration *github.Iteration, journals []*journal.Journal) {
+	in := make(map[string]int)
+	out := make(map[string]int)
+
+	for _, j := range journals {
+		if j.Field.ID != info.FieldID {
+			continue
+		}
+		for _, entry := range j.Applied() {
+			switch iteration.ID {
+			case entry.ToIterationID:
+				r.CarriedIn = addCarryover(r.CarriedIn, in, entry, iterationName(info, entry.FromIterationID, entry.FromIteration))
+			case entry.FromIterationID:
+				r.CarriedOut = addCarryover(r.CarriedOut, out, entry, iterationName(info, entry.ToIterationID, entry.ToIteration))
+			}
+		}
+	}
+}
+
+// addCarryover adds entry to carryovers, replacing an earlier move of the
+// same item, whose position is kept in seen.
+func addCarryover(carryovers []reportCarryover, seen map[string]int, entry journal.Entry, other string) []reportCarryover {
+	carryover := reportCarryover{
+		ItemID:    entry.ItemID,
+		Ref:       entry.Ref,
+		Title:     entry.Title,
+		Iteration: other,
+		Time:      entry.Time.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if i, ok := seen[entry.ItemID]; ok {
+		carryovers[i] = carryover
+		return carryovers
+	}
+	seen[entry.ItemID] = len(carryovers)
+	return append(carryovers, carryover)
+}
+
+// percentComplete returns the share of items completed, e.g. "75%".
+func (r *sprintReport) percentComplete() string {
+	if r.Total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%d%%", r.Completed*100/r.Total)
+}
+
+// reportSection is one table of the report.
+type reportSection struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+	// Empty is shown instead of the table when it has no rows.
+	Empty string
+}
+
+// sections returns the tables making up the report, as shown in the
+// terminal and written as Markdown.
+func (r *sprintReport) sections() []reportSection {
+	groups := func(title, name string, groups []*reportGroup) reportSection {
+		section := reportSection{Title: title, Header: []string{name, "Completed", "Incomplete", "Total"}, Empty: "No items."}
+		for _, group := range groups {
+			section.Rows = append(section.Rows, []string{
+				group.Name, strconv.Itoa(group.Completed), strconv.Itoa(group.Incomplete), strconv.Itoa(group.Total),
+			})
+		}
+		return section
+	}
+
+	mismatches := reportSection{
+		Title:  "Closed vs open mismatches",
+		Header: []string{"Item", "Title", "Repository", "State", "Status", "Problem"},
+		Empty:  "None: every item's state agrees with its status.",
+	}
+	for _, m := range r.Mismatches {
+		mismatches.Rows = append(mismatches.Rows, []string{m.Ref, m.Title, m.Repository, m.State, m.Status, m.Problem})
+	}
+
+	carried := func(title, direction string, carryovers []reportCarryover) reportSection {
+		section := reportSection{Title: title, Header: []string{"Item", "Title", direction, "Moved"}, Empty: "None recorded."}
+		for _, c := range carryovers {
+			section.Rows = append(section.Rows, []string{c.Ref, c.Title, c.Iteration, c.Time})
+		}
+		return section
+	}
+
+	return []reportSection{
+		groups("By status", "Status", r.ByStatus),
+		groups("By repository", "Repository", r.ByRepository),
+		groups("By assignee", "Assignee", r.ByAssignee),
+		mismatches,
+		carried("Carried in", "From", r.CarriedIn),
+		carried("Carried out", "To", r.CarriedOut),
+	}
+}
+
+// WriteMarkdown writes the report as Markdown, for pasting into retros.
+func (r *sprintReport) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s (from %s, %d days)\n\n", r.Iteration.Title, r.Iteration.StartDate, r.Iteration.Duration)
+	fmt.Fprintf(&b, "**%d of %d items completed (%s)**, %d incomplete.\n", r.Completed, r.Total, r.percentComplete(), r.Incomplete)
+
+	for _, section := range r.sections() {
+		fmt.Fprintf(&b, "\n### %s\n\n", section.Title)
+		if len(section.Rows) == 0 {
+			b.WriteString(section.Empty + "\n")
+			continue
+		}
+		if err := output.WriteMarkdownTable(&b, section.Header, section.Rows); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r *sprintReport) Header() []string {
+	return []string{"item_id", "type", "number", "title", "repository", "assignees", "state", "status", "done"}
+}
+
+func (r *sprintReport) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		number := ""
+		if item.Number != 0 {
+			number = strconv.Itoa(item.Number)
+		}
+		rows = append(rows, []string{
+			item.ItemID, item.Type, number, item.Title, item.Repository, strings.Join(item.Assignees, " "),
+			item.State, item.Status, strconv.FormatBool(item.Done),
+		})
+	}
+	return rows
+}
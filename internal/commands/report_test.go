@@ -0,0 +1,147 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/output"
+)
+
+// saveReportJournals records a rollover of PVTI_old from Sprint 0 into
+// Sprint 1 and of PVTI_open out of it into Sprint 2, and a rollover of
+// PVTI_draft and PVTI_late of which only PVTI_draft was undone.
+func saveReportJournals(t *testing.T) {
+	t.Helper()
+
+	project := journal.Project{URL: testProjectURL, ID: testProjectID}
+	field := journal.Field{ID: "F_iter", Name: "Sprint"}
+
+	rollover := journal.New(journal.CommandRollover, project, field, "octocat")
+	rollover.Record(journal.Entry{ItemID: "PVTI_old", Ref: "#7", Title: "Stranded", FromIterationID: "it-0", ToIterationID: "it-1"})
+	rollover.Record(journal.Entry{ItemID: "PVTI_open", Ref: "#1", Title: "Open", FromIterationID: "it-1", ToIterationID: "it-2"})
+	rollover.Record(journal.Entry{ItemID: "PVTI_done", Ref: "#2", Title: "Done", FromIterationID: "it-1", ToIterationID: "it-2", Error: "FORBIDDEN"})
+	if _, err := rollover.Save(""); err != nil {
+		t.Fatal(err)
+	}
+
+	undone := journal.New(journal.CommandRollover, project, field, "octocat")
+	undone.Record(journal.Entry{ItemID: "PVTI_draft", Ref: "draft", Title: "Draft", FromIterationID: "it-1", ToIterationID: "it-2"})
+	undone.Record(journal.Entry{ItemID: "PVTI_late", Ref: "#9", Title: "Late", FromIterationID: "it-1", ToIterationID: "it-2"})
+	path, err := undone.Save("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	undo := journal.New(journal.CommandUndo, project, field, "octocat")
+	undo.Undoes = path
+	undo.Record(journal.Entry{ItemID: "PVTI_draft", Ref: "draft", Title: "Draft", FromIterationID: "it-2", ToIterationID: "it-1"})
+	undo.Record(journal.Entry{ItemID: "PVTI_late", Ref: "#9", Title: "Late", FromIterationID: "it-2", ToIterationID: "it-1", Error: "FORBIDDEN"})
+	if _, err := undo.Save(""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIterationReport(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	saveReportJournals(t)
+
+	var stdout bytes.Buffer
+	opts := &reportOptions{Iteration: "previous", BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Output:     output.JSON,
+		stdout:     &stdout,
+		transport:  newRolloverFake(),
+	}}
+	if err := runIterationReport(context.Background(), opts); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+
+	var report sprintReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+	}
+
+	if report.Iteration.ID != "it-1" || report.Total != 5 || report.Completed != 3 || report.Incomplete != 2 {
+		t.Errorf("report of %s counts %d items, %d completed and %d incomplete; want Sprint 1 with 5, 3 and 2",
+			report.Iteration.Title, report.Total, report.Completed, report.Incomplete)
+	}
+
+	groups := func(groups []*reportGroup) string {
+		var s []string
+		for _, g := range groups {
+			s = append(s, fmt.Sprintf("%s %d/%d", g.Name, g.Completed, g.Total))
+		}
+		return strings.Join(s, ", ")
+	}
+	if got, want := groups(report.ByStatus), "No Status 2/2, Done 1/1, In Progress 0/1, Todo 0/1"; got != want {
+		t.Errorf("by status = %s, want %s", got, want)
+	}
+	if got, want := groups(report.ByRepository), "No repository 3/4, acme/web 0/1"; got != want {
+		t.Errorf("by repository = %s, want %s", got, want)
+	}
+	if got, want := groups(report.ByAssignee), "Unassigned 2/3, octocat 1/2, hubot 1/1"; got != want {
+		t.Errorf("by assignee = %s, want %s", got, want)
+	}
+
+	if len(report.Mismatches) != 1 || report.Mismatches[0].ItemID != "PVTI_done" {
+		t.Errorf("mismatches = %+v, want only the open item marked done", report.Mismatches)
+	}
+	if len(report.CarriedIn) != 1 || report.CarriedIn[0].ItemID != "PVTI_old" || report.CarriedIn[0].Iteration != "Sprint 0" {
+		t.Errorf("carried in = %+v, want PVTI_old from Sprint 0", report.CarriedIn)
+	}
+	// The failed move and the undone one don't count, but the move the undo
+	// failed to put back does
+	var out []string
+	for _, carryover := range report.CarriedOut {
+		out = append(out, carryover.ItemID+" to "+carryover.Iteration)
+	}
+	sort.Strings(out)
+	if got, want := strings.Join(out, ", "), "PVTI_late to Sprint 2, PVTI_open to Sprint 2"; got != want {
+		t.Errorf("carried out = %s, want %s", got, want)
+	}
+}
+
+func TestIterationReportMarkdown(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	var stdout bytes.Buffer
+	opts := &reportOptions{Iteration: "Sprint 1", BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Output:     output.Markdown,
+		stdout:     &stdout,
+		transport:  newRolloverFake(),
+	}}
+	if err := runIterationReport(context.Background(), opts); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+
+	for _, want := range []string{
+		"**3 of 5 items completed (60%)**",
+		"### By assignee\n\n| Assignee | Completed | Incomplete | Total |\n| --- | --- | --- | --- |\n| Unassigned | 2 | 1 | 3 |",
+		"| #2 | Done |  | OPEN | Done | marked done but still open |",
+		"### Carried in\n\nNone recorded.",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("markdown report is missing %q:\n%s", want, stdout.String())
+		}
+	}
+}
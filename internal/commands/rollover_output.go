@@ -0,0 +1,170 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"strconv"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+)
+
+// Rollover decisions for an evaluated item.
+const (
+	decisionDone = "done"
+	decisionMove = "move"
+	decisionSkip = "skip"
+	// decisionExcluded marks items left alone for reaching --max-carryovers.
+	decisionExcluded = "excluded"
+	// decisionFlagged marks items at --max-carryovers that were not
+	// confirmed, because rollover ran silently.
+	decisionFlagged = "flagged"
+)
+
+// Rollover results for an item that was selected to move.
+const (
+	resultMoved  = "moved"
+	resultFailed = "failed"
+	resultDryRun = "dry-run"
+	// resultPlanned marks items written to a plan instead of being moved.
+	resultPlanned = "planned"
+	// resultNotAttempted marks items left alone after an interrupt.
+	resultNotAttempted = "not-attempted"
+)
+
+// projectSummary identifies the project a command ran against.
+type projectSummary struct {
+	URL    string `json:"url" yaml:"url"`
+	Owner  string `json:"owner" yaml:"owner"`
+	Number int    `json:"number" yaml:"number"`
+	ID     string `json:"id" yaml:"id"`
+}
+
+type iterationSummary struct {
+	ID        string `json:"id" yaml:"id"`
+	Title     string `json:"title" yaml:"title"`
+	StartDate string `json:"startDate" yaml:"startDate"`
+	Duration  int    `json:"duration" yaml:"duration"`
+}
+
+func summarizeIteration(iteration *github.Iteration) iterationSummary {
+	return iterationSummary{
+		ID:        iteration.ID,
+		Title:     iteration.Title,
+		StartDate: iteration.StartDate.Format("2006-01-02"),
+		Duration:  iteration.Duration,
+	}
+}
+
+// rolloverItem is an evaluated project item and what happened to it.
+type rolloverItem struct {
+	ItemID     string `json:"itemId" yaml:"itemId"`
+	ContentID  string `json:"contentId" yaml:"contentId"`
+	Type       string `json:"type" yaml:"type"`
+	Number     int    `json:"number,omitempty" yaml:"number,omitempty"`
+	Title      string `json:"title" yaml:"title"`
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	State      string `json:"state,omitempty" yaml:"state,omitempty"`
+	Status     string `json:"status" yaml:"status"`
+	Iteration  string `json:"iteration" yaml:"iteration"`
+	Carryovers int    `json:"carryovers,omitempty" yaml:"carryovers,omitempty"`
+	Decision   string `json:"decision" yaml:"decision"`
+	Result     string `json:"result,omitempty" yaml:"result,omitempty"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// rolloverReport is the document rollover writes with --output.
+type rolloverReport struct {
+	Project projectSummary     `json:"project" yaml:"project"`
+	Field   string             `json:"field" yaml:"field"`
+	From    []iterationSummary `json:"from" yaml:"from"`
+	To      iterationSummary   `json:"to" yaml:"to"`
+	DryRun  bool               `json:"dryRun" yaml:"dryRun"`
+	Items   []*rolloverItem    `json:"items" yaml:"items"`
+	// Journal is where the changes made were recorded.
+	Journal string `json:"journal,omitempty" yaml:"journal,omitempty"`
+
+	byIssue map[*github.Issue]*rolloverItem
+}
+
+func newRolloverReport(project projectSummary, info *projects.IterationInfo, sources []*github.Iteration, to *github.Iteration, dryRun bool) *rolloverReport {
+	report := &rolloverReport{
+		Project: project,
+		Field:   info.FieldName,
+		To:      summarizeIteration(to),
+		DryRun:  dryRun,
+		Items:   []*rolloverItem{},
+		byIssue: make(map[*github.Issue]*rolloverItem),
+	}
+	for _, source := range sources {
+		report.From = append(report.From, summarizeIteration(source))
+	}
+	return report
+}
+
+// evaluate records issue with its initial decision under rule: done items
+// stay put, everything else is skipped until selected.
+func (r *rolloverReport) evaluate(issue *github.Issue, rule *projects.CompletionRule) {
+	item := &rolloverItem{
+		ContentID:  issue.ID,
+		Type:       projects.KindName(issue.Kind),
+		Number:     issue.Number,
+		Title:      issue.Title,
+		State:      issue.State,
+		Status:     projects.GetIssueStatus(issue, rule),
+		Iteration:  issue.IterationTitle,
+		Carryovers: issue.Carryovers,
+		Decision:   decisionSkip,
+	}
+	if len(issue.ProjectItems.Nodes) > 0 {
+		item.ItemID = issue.ProjectItems.Nodes[0].ID
+	}
+	if issue.Repository.Name != "" {
+		item.Repository = issue.Repository.Owner.Login + "/" + issue.Repository.Name
+	}
+	if rule.IsDone(issue) {
+		item.Decision = decisionDone
+	}
+
+	r.Items = append(r.Items, item)
+	r.byIssue[issue] = item
+}
+
+func (r *rolloverReport) item(issue *github.Issue) *rolloverItem {
+	return r.byIssue[issue]
+}
+
+func (r *rolloverReport) Header() []string {
+	return []string{"item_id", "type", "number", "title", "repository", "state", "status", "iteration", "target_iteration", "decision", "result", "error", "carryovers"}
+}
+
+func (r *rolloverReport) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		number := ""
+		if item.Number != 0 {
+			number = strconv.Itoa(item.Number)
+		}
+		carryovers := ""
+		if item.Carryovers != 0 {
+			carryovers = strconv.Itoa(item.Carryovers)
+		}
+		rows = append(rows, []string{
+			item.ItemID, item.Type, number, item.Title, item.Repository, item.State,
+			item.Status, item.Iteration, r.To.Title, item.Decision, item.Result, item.Error, carryovers,
+		})
+	}
+	return rows
+}
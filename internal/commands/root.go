@@ -15,6 +15,7 @@
 package commands
 
 import (
+	"github.com/kriscoleman/gh-projects/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -25,15 +26,29 @@ func SetVersion(v string) {
 }
 
 func NewRootCmd() *cobra.Command {
+	cfg := &config.Config{}
+
 	cmd := &cobra.Command{
 		Use:     "gh-projects",
 		Short:   "A CLI tool for managing GitHub Projects",
 		Long:    `A comprehensive CLI tool for automating GitHub Projects management tasks.`,
 		Version: version,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			loaded, err := config.Load()
+			if err != nil {
+				return err
+			}
+			*cfg = *loaded
+			return nil
+		},
 	}
 
+	cmd.PersistentFlags().Bool("verbose", false, "Print how items were fetched: whether GitHub filtered them and how many pages it took")
+	cmd.PersistentFlags().StringP("output", "o", "", "Output format: json, yaml, csv or markdown (human-readable text then goes to stderr)")
+
 	// Add subcommands
-	cmd.AddCommand(NewIterationCmd())
+	cmd.AddCommand(NewIterationCmd(cfg))
+	cmd.AddCommand(NewConfigCmd(cfg))
 
 	return cmd
-}
\ No newline at end of file
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kriscoleman/gh-projects/internal/config"
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// undoOptions holds the flags specific to iteration undo.
+type undoOptions struct {
+	*BaseCommand
+	BatchSize int
+	Journal   string
+}
+
+func NewIterationUndoCmd(cfg *config.Config) *cobra.Command {
+	opts := &undoOptions{BaseCommand: &BaseCommand{config: cfg}}
+
+	cmd := &cobra.Command{
+		Use:   "undo [journal]",
+		Short: "Move items back to the iterations they were in before a rollover",
+		Long: `Revert the iteration changes recorded in a rollover journal, moving each
+item back to the iteration it was in before.
+
+Without a journal the most recent one that has not been undone yet is used,
+limited to --project when it is given. Items that were moved again since, or
+removed from the project, are left alone.
+
+Undo records its own changes in a journal, so an undo can be undone too.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(cmd); err != nil {
+				return err
+			}
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			ctx, cancel := opts.Context(cmd.Context())
+			defer cancel()
+			return runIterationUndo(ctx, opts, path)
+		},
+	}
+
+	opts.AddCommonFlags(cmd)
+	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", projects.DefaultBatchSize, fmt.Sprintf("Number of items to move per GraphQL request (1 to %d; 1 disables batching)", projects.MaxBatchSize))
+	cmd.Flags().StringVar(&opts.Journal, "journal", "", "Write the journal of this undo to `file` instead of the journal directory")
+
+	return cmd
+}
+
+func runIterationUndo(ctx context.Context, opts *undoOptions, path string) error {
+	base := opts.BaseCommand
+	base.RouteOutput()
+
+	ui.Println("↩️  GitHub Projects - Iteration Undo")
+	ui.Println("===================================")
+
+	if path == "" {
+		dir, err := journal.Dir()
+		if err != nil {
+			return err
+		}
+		if path, err = journal.Latest(dir, base.ProjectURL); err != nil {
+			return err
+		}
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve journal path: %w", err)
+	}
+
+	recorded, err := journal.Load(path)
+	if err != nil {
+		return err
+	}
+	if base.ProjectURL != "" && !recorded.IsFor(base.ProjectURL) {
+		return fmt.Errorf("journal %s is for project %s, not %s", path, recorded.Project.URL, base.ProjectURL)
+	}
+	base.ProjectURL = recorded.Project.URL
+
+	actor := recorded.Actor
+	if actor == "" {
+		actor = "an unknown user"
+	}
+	ui.Printf("📝 Journal: %s\n", path)
+	ui.Printf("📂 Project: %s\n", recorded.Project.URL)
+	ui.Printf("🕒 Recorded %s by %s\n", recorded.StartedAt.Local().Format("Jan 2 15:04"), actor)
+
+	report := &changeReport{
+		Journal: path,
+		Project: recorded.Project.URL,
+		Field:   recorded.Field.Name,
+		DryRun:  base.DryRun,
+		Items:   []*changeItem{},
+	}
+
+	// Pending entries are left by a run that was killed mid-move; the
+	// current iterations checked below tell whether they went through
+	applied := recorded.Undoable()
+	if len(applied) == 0 {
+		ui.Println("\n✅ The journal records no changes to undo.")
+		return base.WriteOutput(report)
+	}
+
+	client, err := base.GetGitHubClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize GitHub client: %w", err)
+	}
+
+	manager := base.NewManager(client, recorded.Project.ID)
+
+	// The field is looked up by ID so that a rename since the rollover
+	// doesn't matter
+	info, err := manager.GetIterations(ctx, recorded.Field.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get iterations: %w", err)
+	}
+	report.Field = info.FieldName
+
+	itemIDs := make([]string, len(applied))
+	for i, entry := range applied {
+		itemIDs[i] = entry.ItemID
+	}
+	current, err := manager.GetItemIterations(ctx, info.FieldName, itemIDs)
+	if err != nil {
+		return err
+	}
+
+	// Each change is undone by the reverse move
+	var restores []journal.Entry
+	var items []*changeItem
+	var skipped []string
+	for _, entry := range applied {
+		restore := journal.Entry{
+			ItemID:          entry.ItemID,
+			Ref:             entry.Ref,
+			Title:           entry.Title,
+			FromIterationID: entry.ToIterationID,
+			FromIteration:   iterationName(info, entry.ToIterationID, entry.ToIteration),
+			ToIterationID:   entry.FromIterationID,
+			ToIteration:     iterationName(info, entry.FromIterationID, entry.FromIteration),
+		}
+		if entry.Carryover != nil {
+			restore.Carryover = entry.Carryover.Reverse()
+		}
+		item := &changeItem{
+			ItemID:    entry.ItemID,
+			Ref:       entry.Ref,
+			Title:     entry.Title,
+			Iteration: restore.FromIteration,
+			Target:    restore.ToIteration,
+		}
+		report.Items = append(report.Items, item)
+
+		state, ok := current[entry.ItemID]
+		switch {
+		case !ok:
+			item.Result, item.Reason = resultSkipped, "no longer in the project"
+		case state.IterationID != entry.ToIterationID:
+			item.Result, item.Reason = resultSkipped, "moved to "+iterationName(info, state.IterationID, "")+" since"
+		case entry.FromIterationID == "":
+			item.Result, item.Reason = resultSkipped, "had no iteration before"
+		default:
+			restores = append(restores, restore)
+			items = append(items, item)
+			continue
+		}
+		skipped = append(skipped, entry.ItemID)
+		ui.Printf("⏭️  Skipping %s: %s\n", describeEntry(entry), item.Reason)
+	}
+
+	if len(restores) == 0 {
+		ui.Println("\n✅ Nothing to undo: every item has changed since.")
+		if !base.DryRun {
+			// Recording the skips stops the journal being offered for undo
+			// again
+			changes := newUndoJournal(ctx, client, recorded, info, path, skipped)
+			if written, err := changes.Save(opts.Journal); err != nil {
+				ui.Printf("⚠️  Warning: %v\n", err)
+			} else {
+				ui.Printf("📝 Journal written to %s\n", written)
+			}
+		}
+		return base.WriteOutput(report)
+	}
+
+	ui.Printf("\n📋 Items to move back (%d):\n", len(restores))
+	ui.Println(strings.Repeat("-", 50))
+	for _, restore := range restores {
+		ui.Printf("• %s → %s\n", describeEntry(restore), restore.ToIteration)
+	}
+
+	if base.DryRun {
+		for _, item := range items {
+			item.Result = resultDryRun
+		}
+		ui.Println("\n🔍 This was a dry run. No changes were made.")
+		return base.WriteOutput(report)
+	}
+
+	if !base.Silent {
+		prompter := ui.NewPrompter()
+		if !prompter.Confirm(ctx, fmt.Sprintf("\nMove %d items back?", len(restores))) {
+			ui.Println("\n❌ Operation cancelled by user")
+			return base.WriteOutput(report)
+		}
+	}
+
+	ui.Println("\n🔄 Moving items back...")
+	changes := newUndoJournal(ctx, client, recorded, info, path, skipped)
+	progress, err := startJournal(changes, restores, opts.Journal)
+	if err != nil {
+		return err
+	}
+	counts := moveItems(ctx, manager, info.FieldID, restores, items, opts.BatchSize, resultRestored, progress)
+	// Items moved back get their carryover counts back even after an
+	// interrupt, so that they match the move
+	if failed := restoreCarryovers(context.WithoutCancel(ctx), client, manager, changes); failed > 0 {
+		ui.Printf("⚠️  %d moved back items still show the carryover count rollover gave them\n", failed)
+	}
+	progress.finish()
+	showMoveSummary(counts, "moved back", len(applied)-len(restores), "skipped")
+
+	if err := base.WriteOutput(report); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("undo stopped before it finished: %w", context.Cause(ctx))
+	}
+	return nil
+}
+
+// newUndoJournal returns the journal for an undo of recorded, read from path,
+// noting the items skipped because they changed since. The journal at path
+// stays open to undo until its changes have all been restored or skipped.
+func newUndoJournal(ctx context.Context, client *github.Client, recorded *journal.Journal, info *projects.IterationInfo, path string, skipped []string) *journal.Journal {
+	changes := journal.New(journal.CommandUndo,
+		recorded.Project,
+		journal.Field{ID: info.FieldID, Name: info.FieldName},
+		viewerLogin(ctx, client))
+	changes.Undoes = path
+	changes.Skipped = skipped
+	return changes
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/journal"
+)
+
+const testProjectURL = "https://github.com/orgs/acme/projects/7"
+
+// rolloverWithJournal runs a silent rollover of Sprint 1 into Sprint 2,
+// writing its journal to path, or to the journal directory when path is
+// empty, and returns the journal written.
+func rolloverWithJournal(t *testing.T, path string) string {
+	t.Helper()
+
+	fake := newRolloverFake()
+	fake.Add(github.GetViewerQuery, nil, `{"data":{"viewer":{"login":"octocat"}}}`)
+	opts := &rolloverOptions{From: "previous", To: "current", Journal: path, BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+	}}
+	if err := runIterationRollover(context.Background(), opts); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+
+	if path == "" {
+		dir, err := journal.Dir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, err = journal.Latest(dir, testProjectURL); err != nil {
+			t.Fatalf("no journal written: %v", err)
+		}
+	}
+	return path
+}
+
+// newUndoFake scripts the lookups undo makes after the rollover above, with
+// the open issue still in Sprint 2 and the draft moved on to Sprint 0.
+func newUndoFake() *github.FakeTransport {
+	fake := newRolloverFake()
+	fake.Add(github.GetItemIterationsQuery, map[string]interface{}{"ids": []string{"PVTI_open", "PVTI_draft"}, "field": "Sprint"}, `{"data":{"nodes":[
+		{"id":"PVTI_open","fieldValueByName":{"iterationId":"it-2","title":"Sprint 2"}},
+		{"id":"PVTI_draft","fieldValueByName":{"iterationId":"it-0","title":"Sprint 0"}}
+	]}}`)
+	fake.Add(github.UpdateItemIterationMutation, map[string]interface{}{
+		"projectId":   testProjectID,
+		"itemId":      "PVTI_open",
+		"fieldId":     "F_iter",
+		"iterationId": "it-1",
+	}, `{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_open"}}}}`)
+	return fake
+}
+
+func TestIterationRolloverJournal(t *testing.T) {
+	path := rolloverWithJournal(t, filepath.Join(t.TempDir(), "rollover.json"))
+
+	recorded, err := journal.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if recorded.Command != journal.CommandRollover || recorded.Actor != "octocat" || recorded.Project.ID != testProjectID || recorded.Field.ID != "F_iter" {
+		t.Errorf("unexpected journal header: %+v", recorded)
+	}
+
+	var got []string
+	for _, entry := range recorded.Entries {
+		got = append(got, fmt.Sprintf("%s:%s->%s", entry.ItemID, entry.FromIterationID, entry.ToIterationID))
+	}
+	want := []string{"PVTI_open:it-1->it-2", "PVTI_draft:it-1->it-2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("journal entries = %v, want %v", got, want)
+	}
+}
+
+func TestIterationRolloverUnwritableJournal(t *testing.T) {
+	fake := newRolloverFake()
+	opts := &rolloverOptions{From: "previous", To: "current", Journal: filepath.Join(t.TempDir(), "missing", "rollover.json"), BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+	}}
+
+	if err := runIterationRollover(context.Background(), opts); err == nil {
+		t.Fatal("rollover succeeded without a journal")
+	}
+	if got := movedItems(fake); len(got) != 0 {
+		t.Errorf("moved %v without a journal, want nothing moved", got)
+	}
+}
+
+func TestIterationUndo(t *testing.T) {
+	dir := t.TempDir()
+	path := rolloverWithJournal(t, filepath.Join(dir, "rollover.json"))
+
+	fake := newUndoFake()
+	opts := &undoOptions{Journal: filepath.Join(dir, "undo.json"), BaseCommand: &BaseCommand{
+		Silent:    true,
+		transport: fake,
+	}}
+	if err := runIterationUndo(context.Background(), opts, path); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	mutations := fake.CallsFor(github.UpdateItemIterationMutation)
+	if len(mutations) != 1 || mutations[0].Variables["itemId"] != "PVTI_open" || mutations[0].Variables["iterationId"] != "it-1" {
+		t.Fatalf("got mutations %v, want only PVTI_open moved back to it-1", mutations)
+	}
+
+	undone, err := journal.Load(opts.Journal)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if undone.Command != journal.CommandUndo || undone.Undoes != path {
+		t.Errorf("undo journal = %+v, want it to undo %s", undone, path)
+	}
+	if len(undone.Entries) != 1 || undone.Entries[0].FromIterationID != "it-2" || undone.Entries[0].ToIterationID != "it-1" {
+		t.Errorf("undo journal entries = %+v", undone.Entries)
+	}
+}
+
+func TestIterationUndoDryRun(t *testing.T) {
+	path := rolloverWithJournal(t, filepath.Join(t.TempDir(), "rollover.json"))
+
+	fake := newUndoFake()
+	opts := &undoOptions{BaseCommand: &BaseCommand{
+		Silent:    true,
+		DryRun:    true,
+		transport: fake,
+	}}
+	if err := runIterationUndo(context.Background(), opts, path); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	if got := movedItems(fake); len(got) != 0 {
+		t.Errorf("dry run moved %v, want nothing", got)
+	}
+}
+
+func TestIterationUndoLatest(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	rolloverWithJournal(t, "")
+
+	opts := &undoOptions{BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  newUndoFake(),
+	}}
+	if err := runIterationUndo(context.Background(), opts, ""); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	// The rollover has been undone, and the undo itself is not picked up
+	opts.transport = newUndoFake()
+	if err := runIterationUndo(context.Background(), opts, ""); err == nil {
+		t.Error("second undo found a journal, want none left to undo")
+	}
+}
+
+func TestIterationUndoFailedLeavesJournal(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	path := rolloverWithJournal(t, "")
+
+	// PVTI_draft has moved on since, and moving PVTI_open back fails
+	fake := newRolloverFake()
+	fake.Add(github.GetItemIterationsQuery, map[string]interface{}{"ids": []string{"PVTI_open", "PVTI_draft"}, "field": "Sprint"}, `{"data":{"nodes":[
+		{"id":"PVTI_open","fieldValueByName":{"iterationId":"it-2","title":"Sprint 2"}},
+		{"id":"PVTI_draft","fieldValueByName":{"iterationId":"it-0","title":"Sprint 0"}}
+	]}}`)
+	fake.Add(github.UpdateItemIterationMutation, map[string]interface{}{
+		"projectId":   testProjectID,
+		"itemId":      "PVTI_open",
+		"fieldId":     "F_iter",
+		"iterationId": "it-1",
+	}, `{"errors":[{"type":"FORBIDDEN","message":"Resource not accessible"}]}`)
+	opts := &undoOptions{BaseCommand: &BaseCommand{
+		ProjectURL: testProjectURL,
+		Silent:     true,
+		transport:  fake,
+	}}
+	if err := runIterationUndo(context.Background(), opts, ""); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	// PVTI_open still stands, so the rollover is still the one to undo
+	dir, err := journal.Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := journal.Latest(dir, testProjectURL); err != nil || got != path {
+		t.Fatalf("Latest after a failed undo = %q, %v; want %s", got, err, path)
+	}
+
+	// A second undo moves PVTI_open back and finishes the job
+	opts.transport = newUndoFake()
+	if err := runIterationUndo(context.Background(), opts, ""); err != nil {
+		t.Fatalf("second undo: %v", err)
+	}
+	if got, err := journal.Latest(dir, testProjectURL); err == nil {
+		t.Errorf("Latest after the undo was finished = %s, want none", got)
+	}
+}
+
+func TestIterationUndoWrongProject(t *testing.T) {
+	path := rolloverWithJournal(t, filepath.Join(t.TempDir(), "rollover.json"))
+
+	opts := &undoOptions{BaseCommand: &BaseCommand{
+		ProjectURL: "https://github.com/orgs/acme/projects/8",
+		Silent:     true,
+		transport:  newUndoFake(),
+	}}
+	if err := runIterationUndo(context.Background(), opts, path); err == nil {
+		t.Error("undo accepted a journal for another project")
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/config"
+	"github.com/kriscoleman/gh-projects/internal/output"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+	"github.com/kriscoleman/gh-projects/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// velocityOptions holds the flags specific to iteration velocity.
+type velocityOptions struct {
+	*BaseCommand
+	Last        int
+	PointsField string
+	Window      int
+}
+
+func NewIterationVelocityCmd(cfg *config.Config) *cobra.Command {
+	opts := &velocityOptions{BaseCommand: &BaseCommand{config: cfg}}
+
+	cmd := &cobra.Command{
+		Use:   "velocity",
+		Short: "Show how much work was done in recent iterations",
+		Long: `Add up the work done in each of the last completed iterations, with a
+rolling average and the standard deviation across them.
+
+Work is measured in a project number field given with --points-field, such as
+"Estimate" or "Points", or in items done when no field is given. Items that
+were rolled over out of an iteration are counted in the iteration they
+finished in.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(cmd); err != nil {
+				return err
+			}
+			ctx, cancel := opts.Context(cmd.Context())
+			defer cancel()
+			return runIterationVelocity(ctx, opts)
+		},
+	}
+
+	opts.AddCommonFlags(cmd)
+	opts.AddCompletionFlags(cmd)
+	opts.AddFieldFlag(cmd)
+	opts.AddFilterFlags(cmd)
+	opts.RequireProject()
+	cmd.Flags().IntVar(&opts.Last, "last", 6, "Number of completed iterations to look at (0 for all)")
+	cmd.Flags().StringVar(&opts.PointsField, "points-field", "", "Number `field` to add up, e.g. Estimate (default: count items)")
+	cmd.Flags().IntVar(&opts.Window, "window", 3, "Number of iterations in the rolling average")
+
+	return cmd
+}
+
+func runIterationVelocity(ctx context.Context, opts *velocityOptions) error {
+	base := opts.BaseCommand
+	base.RouteOutput()
+
+	ui.Println("📈 GitHub Projects - Iteration Velocity")
+	ui.Println("=======================================")
+
+	if opts.Last < 0 {
+		return fmt.Errorf("--last must not be negative")
+	}
+	if opts.Window < 1 {
+		return fmt.Errorf("--window must be at least 1")
+	}
+
+	client, err := base.GetGitHubClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize GitHub client: %w", err)
+	}
+
+	owner, number, projectID, err := base.ParseProjectURL(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	ui.Printf("📂 Project: %s/%d\n", owner, number)
+
+	manager := base.NewManager(client, projectID)
+
+	iterationInfo, err := base.GetIterations(ctx, manager, ui.NewPrompter())
+	if err != nil {
+		return err
+	}
+
+	pointsField := ""
+	if opts.PointsField != "" {
+		field, err := manager.GetNumberField(ctx, opts.PointsField)
+		if err != nil {
+			return fmt.Errorf("invalid --points-field: %w", err)
+		}
+		pointsField = field.Name
+	}
+
+	iterations := iterationInfo.Ended(opts.Last, time.Now())
+	if len(iterations) == 0 {
+		return fmt.Errorf("no completed iterations found in %s", iterationInfo.FieldName)
+	}
+
+	rule := base.Completion
+	if rule == nil {
+		rule = projects.DefaultCompletionRule()
+	}
+
+	ui.Printf("\n🔍 Fetching issues from %s...\n", iterationTitles(iterations))
+	issues, err := manager.GetIterationItems(ctx, iterations...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch issues: %w", err)
+	}
+	if len(base.Kinds) > 0 {
+		issues = projects.FilterByKind(issues, base.Kinds)
+	}
+	if len(base.Repositories) > 0 {
+		issues = projects.FilterByRepository(issues, base.Repositories)
+	}
+
+	report := newVelocityReport(projectSummary{
+		URL:    base.ProjectURL,
+		Owner:  owner,
+		Number: number,
+		ID:     projectID,
+	}, iterationInfo, iterations, issues, rule, pointsField, opts.Window)
+
+	if base.Output == output.Text {
+		printVelocityReport(report)
+	}
+	return base.WriteOutput(report)
+}
+
+// printVelocityReport prints the report as a table in the terminal.
+func printVelocityReport(report *velocityReport) {
+	unit := "items"
+	if report.PointsField != "" {
+		unit = report.PointsField
+	}
+
+	ui.Printf("\n📈 Velocity over %d iterations, in %s\n", len(report.Iterations), unit)
+	ui.Println(strings.Repeat("=", 50))
+
+	header := []string{"Iteration", "Start", "Done", "Not done", fmt.Sprintf("Rolling average (%d)", report.Window)}
+	if report.PointsField != "" {
+		header = []string{"Iteration", "Start", "Items done", "Done", "Not done", fmt.Sprintf("Rolling average (%d)", report.Window)}
+	}
+
+	var rows [][]string
+	unestimated := 0
+	for _, velocity := range report.Iterations {
+		row := []string{velocity.Title, velocity.StartDate}
+		if report.PointsField != "" {
+			row = append(row, strconv.Itoa(velocity.ItemsDone))
+		}
+		rows = append(rows, append(row, formatNumber(velocity.Done), formatNumber(velocity.NotDone), formatNumber(velocity.RollingAverage)))
+		unestimated += velocity.Unestimated
+	}
+	ui.PrintTable(header, rows)
+
+	ui.Printf("\nAverage: %s %s per iteration, standard deviation %s\n", formatNumber(report.Average), unit, formatNumber(report.StdDev))
+	if unestimated > 0 {
+		ui.Printf("⚠️  %d items done had no %s and were counted as 0\n", unestimated, report.PointsField)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/projects"
+)
+
+// velocityReport is the document iteration velocity writes with --output.
+type velocityReport struct {
+	Project projectSummary `json:"project" yaml:"project"`
+	Field   string         `json:"field" yaml:"field"`
+	// PointsField is the number field summed, or empty when items are
+	// counted.
+	PointsField string               `json:"pointsField,omitempty" yaml:"pointsField,omitempty"`
+	Window      int                  `json:"window" yaml:"window"`
+	Iterations  []*velocityIteration `json:"iterations" yaml:"iterations"`
+	// Average and StdDev describe the work done per iteration. StdDev is the
+	// sample standard deviation, 0 with fewer than two iterations.
+	Average float64 `json:"average" yaml:"average"`
+	StdDev  float64 `json:"stdDev" yaml:"stdDev"`
+}
+
+// velocityIteration is the work done in one iteration, in points or items.
+type velocityIteration struct {
+	iterationSummary `yaml:",inline"`
+	ItemsDone        int     `json:"itemsDone" yaml:"itemsDone"`
+	Done             float64 `json:"done" yaml:"done"`
+	NotDone          float64 `json:"notDone" yaml:"notDone"`
+	// Unestimated counts the items done without a value in PointsField.
+	Unestimated int `json:"unestimated,omitempty" yaml:"unestimated,omitempty"`
+	// RollingAverage is the average of Done over this iteration and the ones
+	// before it, up to Window iterations.
+	RollingAverage float64 `json:"rollingAverage" yaml:"rollingAverage"`
+}
+
+// newVelocityReport adds up the work in each of iterations, oldest first,
+// from issues. pointsField is the number field to sum, or "" to count items.
+func newVelocityReport(project projectSummary, info *projects.IterationInfo, iterations []*github.Iteration, issues []*github.Issue, rule *projects.CompletionRule, pointsField string, window int) *velocityReport {
+	report := &velocityReport{
+		Project:     project,
+		Field:       info.FieldName,
+		PointsField: pointsField,
+		Window:      window,
+		Iterations:  make([]*velocityIteration, len(iterations)),
+	}
+
+	byID := make(map[string]*velocityIteration, len(iterations))
+	for i, iteration := range iterations {
+		report.Iterations[i] = &velocityIteration{iterationSummary: summarizeIteration(iteration)}
+		byID[iteration.ID] = report.Iterations[i]
+	}
+
+	for _, issue := range issues {
+		velocity, ok := byID[issue.IterationID]
+		if !ok {
+			continue
+		}
+
+		value, estimated := 1.0, true
+		if pointsField != "" {
+			value, estimated = projects.GetNumberValue(issue, pointsField)
+		}
+
+		if !rule.IsDone(issue) {
+			velocity.NotDone += value
+			continue
+		}
+		velocity.ItemsDone++
+		velocity.Done += value
+		if !estimated {
+			velocity.Unestimated++
+		}
+	}
+
+	done := make([]float64, len(report.Iterations))
+	for i, velocity := range report.Iterations {
+		done[i] = velocity.Done
+		first := i - window + 1
+		if first < 0 {
+			first = 0
+		}
+		velocity.RollingAverage = round(mean(done[first : i+1]))
+	}
+	report.Average = round(mean(done))
+	report.StdDev = round(stdDev(done))
+	return report
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stdDev returns the sample standard deviation of values.
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+// round rounds v to two decimal places.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// formatNumber formats v without trailing zeros, e.g. "3" or "2.5".
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (r *velocityReport) Header() []string {
+	return []string{"iteration_id", "title", "start_date", "duration", "items_done", "done", "not_done", "unestimated", "rolling_average"}
+}
+
+func (r *velocityReport) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Iterations))
+	for _, velocity := range r.Iterations {
+		rows = append(rows, []string{
+			velocity.ID, velocity.Title, velocity.StartDate, strconv.Itoa(velocity.Duration),
+			strconv.Itoa(velocity.ItemsDone), formatNumber(velocity.Done), formatNumber(velocity.NotDone),
+			strconv.Itoa(velocity.Unestimated), formatNumber(velocity.RollingAverage),
+		})
+	}
+	return rows
+}
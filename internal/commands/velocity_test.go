@@ -0,0 +1,124 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+	"github.com/kriscoleman/gh-projects/internal/output"
+)
+
+// newVelocityFake scripts a project with an "Estimate" number field and two
+// finished sprints: "Sprint 0" with a done item estimated at 8, and "Sprint 1"
+// with a done item estimated at 5, a done item without an estimate and an
+// open item estimated at 3.
+func newVelocityFake() *github.FakeTransport {
+	fake := github.NewFakeTransport()
+	addProject(fake, fmt.Sprintf(`[
+		{"id":"F_iter","name":"Sprint","dataType":"ITERATION","configuration":{
+			"iterations":[{"id":"it-2","title":"Sprint 2","startDate":%q,"duration":14}],
+			"completedIterations":[
+				{"id":"it-0","title":"Sprint 0","startDate":%q,"duration":14},
+				{"id":"it-1","title":"Sprint 1","startDate":%q,"duration":14}
+			]
+		}},
+		{"id":"F_estimate","name":"Estimate","dataType":"NUMBER"}
+	]`, day(-3), day(-31), day(-17)))
+
+	item := func(id string, iteration, status string, estimate string) string {
+		values := fmt.Sprintf(`{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":%q,"title":"x"},
+			{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":%q}`, iteration, status)
+		if estimate != "" {
+			values += fmt.Sprintf(`,{"__typename":"ProjectV2ItemFieldNumberValue","field":{"id":"F_estimate","name":"Estimate"},"number":%s}`, estimate)
+		}
+		return fmt.Sprintf(`{"id":%q,"content":{"__typename":"Issue","id":"I_%s","number":1,"title":%q,"state":"OPEN"},"fieldValues":{"nodes":[%s]}}`, id, id, id, values)
+	}
+	addItems(fake, "["+
+		item("PVTI_a", "it-0", "Done", "8")+","+
+		item("PVTI_b", "it-1", "Done", "5")+","+
+		item("PVTI_c", "it-1", "Done", "")+","+
+		item("PVTI_d", "it-1", "In Progress", "3")+","+
+		item("PVTI_e", "it-2", "Done", "13")+
+		"]")
+	return fake
+}
+
+func TestIterationVelocity(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   velocityOptions
+		want   string
+		avg    float64
+		stdDev float64
+	}{
+		{
+			name:   "items",
+			opts:   velocityOptions{Window: 3},
+			want:   "[Sprint 0: 1 done, 0 not done, rolling 1 Sprint 1: 2 done, 1 not done, rolling 1.5]",
+			avg:    1.5,
+			stdDev: 0.71,
+		},
+		{
+			name:   "points",
+			opts:   velocityOptions{PointsField: "estimate", Window: 1},
+			want:   "[Sprint 0: 8 done, 0 not done, rolling 8 Sprint 1: 5 done, 3 not done, rolling 5]",
+			avg:    6.5,
+			stdDev: 2.12,
+		},
+		{
+			name: "last",
+			opts: velocityOptions{Last: 1, Window: 3},
+			want: "[Sprint 1: 2 done, 1 not done, rolling 2]",
+			avg:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := tt.opts
+			opts.BaseCommand = &BaseCommand{
+				ProjectURL: testProjectURL,
+				Output:     output.JSON,
+				stdout:     &stdout,
+				transport:  newVelocityFake(),
+			}
+			if err := runIterationVelocity(context.Background(), &opts); err != nil {
+				t.Fatalf("velocity: %v", err)
+			}
+
+			var report velocityReport
+			if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+				t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+			}
+
+			var got []string
+			for _, velocity := range report.Iterations {
+				got = append(got, fmt.Sprintf("%s: %v done, %v not done, rolling %v", velocity.Title, velocity.Done, velocity.NotDone, velocity.RollingAverage))
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("iterations = %v, want %v", got, tt.want)
+			}
+			if report.Average != tt.avg || report.StdDev != tt.stdDev {
+				t.Errorf("average %v, standard deviation %v; want %v and %v", report.Average, report.StdDev, tt.avg, tt.stdDev)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the repo-local configuration file.
+const FileName = ".gh-projects.yaml"
+
+// Settings are the values a configuration file can set, either at the top
+// level or inside a profile.
+type Settings struct {
+	Project        string      `json:"project,omitempty" yaml:"project,omitempty"`
+	IterationField string      `json:"iterationField,omitempty" yaml:"iteration-field,omitempty"`
+	Silent         *bool       `json:"silent,omitempty" yaml:"silent,omitempty"`
+	DryRun         *bool       `json:"dryRun,omitempty" yaml:"dry-run,omitempty"`
+	Completion     *Completion `json:"completion,omitempty" yaml:"completion,omitempty"`
+	Filters        *Filters    `json:"filters,omitempty" yaml:"filters,omitempty"`
+}
+
+// Completion configures when a project item counts as done.
+type Completion struct {
+	Fields       []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	DoneValues   []string `json:"doneValues,omitempty" yaml:"done-values,omitempty"`
+	DonePattern  string   `json:"donePattern,omitempty" yaml:"done-pattern,omitempty"`
+	ClosedIsDone *bool    `json:"closedIsDone,omitempty" yaml:"closed-is-done,omitempty"`
+}
+
+// Filters restrict which project items commands act on.
+type Filters struct {
+	Repositories []string `json:"repositories,omitempty" yaml:"repositories,omitempty"`
+	Types        []string `json:"types,omitempty" yaml:"types,omitempty"`
+}
+
+// File is the contents of a configuration file. Top-level settings apply to
+// every profile.
+type File struct {
+	Settings       `yaml:",inline"`
+	DefaultProfile string              `yaml:"default-profile,omitempty"`
+	Profiles       map[string]Settings `yaml:"profiles,omitempty"`
+}
+
+// Config is the merged configuration from every file that was found.
+type Config struct {
+	File
+	// Paths lists the files that were loaded, lowest precedence first.
+	Paths []string
+}
+
+// Load reads the user configuration from $XDG_CONFIG_HOME/gh-projects and the
+// repo-local FileName found in the working directory or one of its parents,
+// with the repo-local file taking precedence.
+func Load() (*Config, error) {
+	cfg := &Config{}
+
+	var paths []string
+	if path, err := userConfigPath(); err == nil {
+		paths = append(paths, path)
+	}
+	if path, ok := findRepoConfig(); ok {
+		paths = append(paths, path)
+	}
+
+	for _, path := range paths {
+		file, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if file == nil {
+			continue
+		}
+		cfg.File = cfg.File.merge(*file)
+		cfg.Paths = append(cfg.Paths, path)
+	}
+
+	return cfg, nil
+}
+
+// LoadFile reads the configuration file at path. A missing file yields nil.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Profile returns the top-level settings overlaid with the named profile. An
+// empty name selects the default profile, if one is configured.
+func (c *Config) Profile(name string) (Settings, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return c.Settings, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return Settings{}, fmt.Errorf("profile %q not found (available: %v)", name, c.ProfileNames())
+	}
+	return c.Settings.Merge(profile), nil
+}
+
+// ProfileNames returns the configured profile names in sorted order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Merge returns s with every value set in over replacing its own.
+func (s Settings) Merge(over Settings) Settings {
+	if over.Project != "" {
+		s.Project = over.Project
+	}
+	if over.IterationField != "" {
+		s.IterationField = over.IterationField
+	}
+	if over.Silent != nil {
+		s.Silent = over.Silent
+	}
+	if over.DryRun != nil {
+		s.DryRun = over.DryRun
+	}
+	if over.Completion != nil {
+		s.Completion = s.Completion.merge(over.Completion)
+	}
+	if over.Filters != nil {
+		s.Filters = s.Filters.merge(over.Filters)
+	}
+	return s
+}
+
+func (c *Completion) merge(over *Completion) *Completion {
+	merged := Completion{}
+	if c != nil {
+		merged = *c
+	}
+	if len(over.Fields) > 0 {
+		merged.Fields = over.Fields
+	}
+	if len(over.DoneValues) > 0 {
+		merged.DoneValues = over.DoneValues
+	}
+	if over.DonePattern != "" {
+		merged.DonePattern = over.DonePattern
+	}
+	if over.ClosedIsDone != nil {
+		merged.ClosedIsDone = over.ClosedIsDone
+	}
+	return &merged
+}
+
+func (f *Filters) merge(over *Filters) *Filters {
+	merged := Filters{}
+	if f != nil {
+		merged = *f
+	}
+	if len(over.Repositories) > 0 {
+		merged.Repositories = over.Repositories
+	}
+	if len(over.Types) > 0 {
+		merged.Types = over.Types
+	}
+	return &merged
+}
+
+func (f File) merge(over File) File {
+	f.Settings = f.Settings.Merge(over.Settings)
+	if over.DefaultProfile != "" {
+		f.DefaultProfile = over.DefaultProfile
+	}
+	if len(over.Profiles) > 0 {
+		profiles := make(map[string]Settings, len(f.Profiles)+len(over.Profiles))
+		for name, profile := range f.Profiles {
+			profiles[name] = profile
+		}
+		for name, profile := range over.Profiles {
+			profiles[name] = profiles[name].Merge(profile)
+		}
+		f.Profiles = profiles
+	}
+	return f
+}
+
+// userConfigPath returns the path of the per-user configuration file.
+func userConfigPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gh-projects", "config.yaml"), nil
+}
+
+// findRepoConfig looks for FileName in the working directory and its parents,
+// stopping at the root of the enclosing git repository.
+func findRepoConfig() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", false
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
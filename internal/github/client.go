@@ -15,166 +15,195 @@
 package github
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"log"
 	"os/exec"
-	"strings"
-	
+
 	"github.com/google/go-github/v67/github"
 )
 
 type Client struct {
 	authenticated bool
-	ghClient     *github.Client
-	useToken     bool
+	ghClient      *github.Client
+	transport     Transport
+	issues        IssueWriter
+	token         string
+	host          string
+	limits        *rateLimitState
 }
 
-func NewClient(token string) (*Client, error) {
-	client := &Client{}
-	
+// NewClient returns a client for host, resolved with ResolveHost when it is
+// empty. It authenticates with token, the token the environment provides for
+// the host, or the GitHub CLI's token, and talks to the API in process. Only
+// when no token can be found does it fall back to running `gh api` for every
+// request.
+func NewClient(ctx context.Context, token, host string) (*Client, error) {
+	host = ResolveHost(host)
+	client := &Client{host: host}
+
+	if token == "" {
+		token = tokenFromEnv(host)
+	}
+	if token == "" {
+		resolved, err := ghToken(ctx, host)
+		if err != nil {
+			log.Printf("Could not resolve a GitHub CLI token for %s, falling back to gh api: %v", host, err)
+		}
+		token = resolved
+	}
+
 	if token != "" {
+		client.token = token
 		client.ghClient = github.NewClient(nil).WithAuthToken(token)
-		client.useToken = true
-		client.authenticated = true
-		return client, nil
-	}
-	
-	if tokenFromEnv := os.Getenv("GITHUB_TOKEN"); tokenFromEnv != "" {
-		client.ghClient = github.NewClient(nil).WithAuthToken(tokenFromEnv)
-		client.useToken = true
+		if IsEnterprise(host) {
+			// Uploads are never used, so the API root stands in for them
+			enterprise, err := client.ghClient.WithEnterpriseURLs(restBaseURL(host), restBaseURL(host))
+			if err != nil {
+				return nil, fmt.Errorf("invalid GitHub host %q: %w", host, err)
+			}
+			client.ghClient = enterprise
+		}
+		client.limits = &rateLimitState{}
+		client.transport = newRetryTransport(&httpTransport{
+			client:   client.ghClient.Client(),
+			endpoint: GraphQLEndpoint(host),
+			limits:   client.limits,
+		}, client.limits)
+		client.issues = &restIssueWriter{client: client.ghClient}
 		client.authenticated = true
 		return client, nil
 	}
-	
-	if err := client.checkAuth(); err != nil {
+
+	if err := client.checkAuth(ctx); err != nil {
 		return nil, fmt.Errorf("GitHub CLI authentication failed: %w", err)
 	}
+	client.transport = newRetryTransport(&cliTransport{host: host}, nil)
+	client.issues = &cliIssueWriter{host: host}
 	client.authenticated = true
 	return client, nil
 }
 
-func (c *Client) checkAuth() error {
-	cmd := exec.Command("gh", "auth", "status")
-	output, err := cmd.CombinedOutput()
+// NewClientWithTransport returns a client that sends every GraphQL request
+// through transport. No authentication is performed.
+func NewClientWithTransport(transport Transport) *Client {
+	return &Client{
+		authenticated: true,
+		transport:     transport,
+	}
+}
+
+// Issues returns the client's REST writer for issue comments and labels. It
+// fails for clients created with NewClientWithTransport unless one was set
+// with SetIssueWriter.
+func (c *Client) Issues() (IssueWriter, error) {
+	if c.issues == nil {
+		return nil, fmt.Errorf("comments and labels need a connection to GitHub and are not available with this client")
+	}
+	return c.issues, nil
+}
+
+// SetIssueWriter replaces the writer returned by Issues, e.g. in tests.
+func (c *Client) SetIssueWriter(w IssueWriter) {
+	c.issues = w
+}
+
+// Record writes every GraphQL exchange made by the client to dir as fixture
+// files, with credentials scrubbed, so the session can be replayed offline
+// with NewReplayTransport.
+func (c *Client) Record(dir string) error {
+	recorder, err := newRecordingTransport(c.transport, dir, c.token)
 	if err != nil {
-		return fmt.Errorf("not authenticated with GitHub CLI: %s", string(output))
+		return err
 	}
+	c.transport = recorder
 	return nil
 }
 
-func (c *Client) GraphQL(query string, variables map[string]interface{}) (map[string]interface{}, error) {
-	if c.useToken {
-		return c.executeGraphQLWithToken(query, variables)
-	}
-	return c.executeGraphQLWithCLI(query, variables)
+// RateLimit returns the GraphQL rate limit budget reported by GitHub's most
+// recent response. It is unknown until a request has been made, and in gh CLI
+// mode, which does not expose response headers.
+func (c *Client) RateLimit() (RateLimit, bool) {
+	return c.limits.get()
 }
 
-func (c *Client) executeGraphQLWithToken(query string, variables map[string]interface{}) (map[string]interface{}, error) {
-	req := struct {
-		Query     string                 `json:"query"`
-		Variables map[string]interface{} `json:"variables,omitempty"`
-	}{
-		Query:     query,
-		Variables: variables,
-	}
-	
-	reqBody, err := json.Marshal(req)
+// Host returns the GitHub host the client talks to; it is empty for clients
+// created with NewClientWithTransport.
+func (c *Client) Host() string {
+	return c.host
+}
+
+func (c *Client) checkAuth(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "gh", "auth", "status", "--hostname", c.host)
+	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal GraphQL request: %w", err)
+		return fmt.Errorf("not authenticated with GitHub CLI: %s", string(output))
 	}
-	
-	resp, err := c.ghClient.Client().Post("https://api.github.com/graphql", "application/json", bytes.NewReader(reqBody))
+	return nil
+}
+
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}) (map[string]interface{}, error) {
+	body, err := c.DoGraphQL(ctx, query, variables)
 	if err != nil {
-		return nil, fmt.Errorf("GraphQL request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-	
+
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode GraphQL response: %w", err)
 	}
-	
-	if errors, ok := result["errors"]; ok {
-		return nil, fmt.Errorf("GraphQL errors: %v", errors)
-	}
-	
 	return result, nil
 }
 
-func (c *Client) executeGraphQLWithCLI(query string, variables map[string]interface{}) (map[string]interface{}, error) {
-	args := []string{"api", "graphql", "-f", fmt.Sprintf("query=%s", query)}
-	
-	for key, value := range variables {
-		switch v := value.(type) {
-		case int:
-			args = append(args, "-F", fmt.Sprintf("%s=%d", key, v))
-		case float64:
-			args = append(args, "-F", fmt.Sprintf("%s=%d", key, int(v)))
-		default:
-			args = append(args, "-f", fmt.Sprintf("%s=%v", key, value))
-		}
-	}
-	
-	cmd := exec.Command("gh", args...)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	
-	err := cmd.Run()
-	
-	// Try to parse the output regardless of error status
-	// gh returns non-zero exit code even for partial GraphQL errors
-	var result map[string]interface{}
-	if parseErr := json.Unmarshal(stdout.Bytes(), &result); parseErr != nil {
-		// If we can't parse and there was an exec error, return the exec error
-		if err != nil {
-			return nil, fmt.Errorf("GraphQL query failed: %v\nStderr: %s\nStdout: %s", err, stderr.String(), stdout.String())
-		}
-		// Otherwise return the parse error
-		return nil, fmt.Errorf("failed to parse GraphQL response: %w\nOutput: %s", parseErr, stdout.String())
+// DoGraphQL executes a query through the client's transport and returns the
+// raw response body. Responses that carry data are returned even when GitHub
+// reported errors alongside it; responses without data fail with a
+// GraphQLErrors.
+func (c *Client) DoGraphQL(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
+	body, err := c.transport.Execute(ctx, query, variables)
+	if err != nil {
+		return nil, err
 	}
-	
-	// Check if we have data - partial errors are OK for queries that try multiple paths
-	if data, hasData := result["data"]; hasData && data != nil {
-		return result, nil
+	if err := checkResponse(body); err != nil {
+		return nil, err
 	}
-	
-	// No data at all - this is a real error
+	return body, nil
+}
+
+// GraphQLResponse executes a query and decodes both the response's data
+// object and the errors returned alongside it, for callers that decide for
+// themselves which errors are fatal.
+func GraphQLResponse[T any](ctx context.Context, doer GraphQLDoer, query string, variables map[string]interface{}) (*Response[T], error) {
+	body, err := doer.DoGraphQL(ctx, query, variables)
 	if err != nil {
-		return nil, fmt.Errorf("GraphQL query failed: %v\nStderr: %s", err, stderr.String())
+		return nil, err
+	}
+
+	var response Response[T]
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode GraphQL response: %w", err)
 	}
-	
-	if errors, ok := result["errors"]; ok {
-		return nil, fmt.Errorf("GraphQL errors: %v", errors)
+	if response.Data == nil {
+		if len(response.Errors) > 0 {
+			return nil, response.Errors
+		}
+		return nil, fmt.Errorf("invalid GraphQL response: missing data")
 	}
-	
-	return result, nil
+	return &response, nil
 }
 
-func (c *Client) ParseProjectURL(url string) (owner, number string, err error) {
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	url = strings.TrimSuffix(url, "/")
-	
-	parts := strings.Split(url, "/")
-	
-	if !strings.Contains(url, "github.com") || !strings.Contains(url, "/projects/") {
-		return "", "", fmt.Errorf("invalid GitHub project URL format: must be github.com URL with /projects/")
-	}
-	
-	for i, part := range parts {
-		if part == "github.com" && i+2 < len(parts) && parts[i+2] == "projects" && i+3 < len(parts) {
-			return parts[i+1], parts[i+3], nil
-		}
-		if part == "users" && i+1 < len(parts) && i+2 < len(parts) && parts[i+2] == "projects" && i+3 < len(parts) {
-			return parts[i+1], parts[i+3], nil
-		}
-		if part == "orgs" && i+1 < len(parts) && i+2 < len(parts) && parts[i+2] == "projects" && i+3 < len(parts) {
-			return parts[i+1], parts[i+3], nil
-		}
+// GraphQLTyped executes a query and decodes the response's data object into T.
+// It is the typed counterpart of Client.GraphQL; callers are expected to check
+// the pointers in T for the paths they rely on. Tolerated errors such as
+// NOT_FOUND are ignored, any other error fails the call.
+func GraphQLTyped[T any](ctx context.Context, doer GraphQLDoer, query string, variables map[string]interface{}) (*T, error) {
+	response, err := GraphQLResponse[T](ctx, doer, query, variables)
+	if err != nil {
+		return nil, err
+	}
+	if fatal := response.Errors.Fatal(); len(fatal) > 0 {
+		return nil, fatal
 	}
-	
-	return "", "", fmt.Errorf("could not parse project URL: expected format https://github.com/{owner}/projects/{number}")
-}
\ No newline at end of file
+	return response.Data, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GraphQLError is one entry of a GraphQL response's errors array.
+type GraphQLError struct {
+	// Type is GitHub's error code, e.g. NOT_FOUND, FORBIDDEN or RATE_LIMITED.
+	Type    string        `json:"type,omitempty"`
+	Path    []interface{} `json:"path,omitempty"`
+	Message string        `json:"message"`
+}
+
+func (e *GraphQLError) Error() string {
+	msg := e.Message
+	if path := e.PathString(); path != "" {
+		msg = path + ": " + msg
+	}
+	if e.Type != "" {
+		msg += " (" + e.Type + ")"
+	}
+	return msg
+}
+
+// PathString returns the error's path in dotted form, e.g. "node.items".
+func (e *GraphQLError) PathString() string {
+	segments := make([]string, len(e.Path))
+	for i, segment := range e.Path {
+		segments[i] = fmt.Sprint(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+// Tolerated reports whether the error only means part of the query resolved
+// to nothing, leaving the rest of the data usable. A query that looks a
+// project up under both a user and an organization always gets one.
+func (e *GraphQLError) Tolerated() bool {
+	return e.Type == "NOT_FOUND"
+}
+
+// GraphQLErrors is the errors array of a GraphQL response.
+type GraphQLErrors []*GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "GraphQL errors: " + strings.Join(messages, "; ")
+}
+
+// Fatal returns the errors that are not tolerated, or nil when there are
+// none.
+func (e GraphQLErrors) Fatal() GraphQLErrors {
+	var fatal GraphQLErrors
+	for _, err := range e {
+		if !err.Tolerated() {
+			fatal = append(fatal, err)
+		}
+	}
+	return fatal
+}
+
+// Rejected reports whether the errors are GitHub refusing the query before
+// running it, as it does when the query uses a field or argument its schema
+// lacks. Such errors come without data or a type, unlike failures such as
+// FORBIDDEN or RATE_LIMITED.
+func (e GraphQLErrors) Rejected() bool {
+	if len(e) == 0 {
+		return false
+	}
+	for _, err := range e {
+		if err.Type != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// At returns the first error whose path starts at the top-level field or
+// alias name, or nil.
+func (e GraphQLErrors) At(name string) *GraphQLError {
+	for _, err := range e {
+		if len(err.Path) > 0 && fmt.Sprint(err.Path[0]) == name {
+			return err
+		}
+	}
+	return nil
+}
+
+// Response is a decoded GraphQL response: the data object together with any
+// errors GitHub returned alongside it.
+type Response[T any] struct {
+	Data   *T            `json:"data"`
+	Errors GraphQLErrors `json:"errors"`
+}
+
+// checkResponse applies the response policy shared by every transport. A
+// response carrying data is accepted whatever errors accompany it, leaving
+// callers to judge them; a response without data fails with its errors.
+func checkResponse(body []byte) error {
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+
+	if len(envelope.Data) > 0 && string(envelope.Data) != "null" {
+		return nil
+	}
+	if len(envelope.Errors) > 0 {
+		return envelope.Errors
+	}
+	return fmt.Errorf("invalid GraphQL response: missing data")
+}
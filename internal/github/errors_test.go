@@ -0,0 +1,76 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGraphQLResponsePolicy(t *testing.T) {
+	vars := map[string]interface{}{"owner": "acme", "number": 7}
+	tests := []struct {
+		name      string
+		response  string
+		wantData  bool
+		wantFatal string
+	}{
+		{
+			name: "tolerated not found",
+			response: `{"data":{"user":null,"organization":{"projectV2":{"id":"PVT_1"}}},
+				"errors":[{"type":"NOT_FOUND","path":["user"],"message":"Could not resolve to a User"}]}`,
+			wantData: true,
+		},
+		{
+			name: "forbidden alongside data",
+			response: `{"data":{"user":null,"organization":null},
+				"errors":[{"type":"FORBIDDEN","path":["organization"],"message":"SAML enforcement"}]}`,
+			wantData:  true,
+			wantFatal: "organization: SAML enforcement (FORBIDDEN)",
+		},
+		{
+			name:      "no data",
+			response:  `{"data":null,"errors":[{"type":"INTERNAL","message":"Something went wrong"}]}`,
+			wantFatal: "Something went wrong (INTERNAL)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeTransport()
+			fake.Add(GetProjectQuery, vars, tt.response)
+			client := NewClientWithTransport(fake)
+
+			response, err := GraphQLResponse[ProjectResponse](context.Background(), client, GetProjectQuery, vars)
+			if (response != nil) != tt.wantData {
+				t.Fatalf("GraphQLResponse = %+v, %v; want data %v", response, err, tt.wantData)
+			}
+
+			typed, err := GraphQLTyped[ProjectResponse](context.Background(), client, GetProjectQuery, vars)
+			if tt.wantFatal == "" {
+				if err != nil || typed.Organization == nil || typed.Organization.ProjectV2 == nil {
+					t.Fatalf("GraphQLTyped = %+v, %v; want the organization project", typed, err)
+				}
+				return
+			}
+
+			var gqlErrs GraphQLErrors
+			if !errors.As(err, &gqlErrs) || len(gqlErrs) != 1 || gqlErrs[0].Error() != tt.wantFatal {
+				t.Fatalf("GraphQLTyped error = %v, want GraphQLErrors with %q", err, tt.wantFatal)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeCall is a request received by a FakeTransport.
+type FakeCall struct {
+	Query     string
+	Variables map[string]interface{}
+}
+
+// FakeTransport is an in-memory Transport that replays canned responses keyed
+// by query and variables. Responses registered for the same key are served in
+// order, with the last one repeated once the script runs out.
+type FakeTransport struct {
+	mu        sync.Mutex
+	responses map[string][]fakeResponse
+	// fallbacks are replies to a query whatever its variables, keyed by the
+	// normalized query.
+	fallbacks map[string]string
+	calls     []FakeCall
+}
+
+type fakeResponse struct {
+	body string
+	err  error
+}
+
+func NewFakeTransport() *FakeTransport {
+	return &FakeTransport{
+		responses: make(map[string][]fakeResponse),
+		fallbacks: make(map[string]string),
+	}
+}
+
+// Add scripts response as the next reply to query with variables.
+func (f *FakeTransport) Add(query string, variables map[string]interface{}, response string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fakeKey(query, variables)
+	f.responses[key] = append(f.responses[key], fakeResponse{body: response})
+}
+
+// AddError scripts err as the next reply to query with variables.
+func (f *FakeTransport) AddError(query string, variables map[string]interface{}, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fakeKey(query, variables)
+	f.responses[key] = append(f.responses[key], fakeResponse{err: err})
+}
+
+// AddAny scripts response as the reply to query with any variables that have
+// no script of their own.
+func (f *FakeTransport) AddAny(query string, response string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fallbacks[normalizeQuery(query)] = response
+}
+
+func (f *FakeTransport) Execute(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeCall{Query: query, Variables: variables})
+
+	key := fakeKey(query, variables)
+	script := f.responses[key]
+	if len(script) == 0 {
+		if response, ok := f.fallbacks[normalizeQuery(query)]; ok {
+			return []byte(response), nil
+		}
+		vars, _ := json.Marshal(variables)
+		return nil, fmt.Errorf("fake transport: no response scripted for query %q with variables %s", queryName(query), vars)
+	}
+
+	response := script[0]
+	if len(script) > 1 {
+		f.responses[key] = script[1:]
+	}
+	if response.err != nil {
+		return nil, response.err
+	}
+	return []byte(response.body), nil
+}
+
+// Calls returns the requests received so far, in order.
+func (f *FakeTransport) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// CallsFor returns the requests received for query, in order.
+func (f *FakeTransport) CallsFor(query string) []FakeCall {
+	var calls []FakeCall
+	for _, call := range f.Calls() {
+		if normalizeQuery(call.Query) == normalizeQuery(query) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func fakeKey(query string, variables map[string]interface{}) string {
+	// encoding/json sorts map keys, which makes the encoding canonical
+	vars, _ := json.Marshal(variables)
+	if len(variables) == 0 {
+		vars = []byte("{}")
+	}
+	return normalizeQuery(query) + "\x00" + string(vars)
+}
+
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// queryName returns a short description of query for error messages.
+func queryName(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) > 3 {
+		fields = fields[:3]
+	}
+	return strings.Join(fields, " ")
+}
+
+// FakeIssueCall is a request received by a FakeIssueWriter. Body is set for
+// comments, Labels for labels added and Removed for a label removed.
+type FakeIssueCall struct {
+	Owner   string
+	Repo    string
+	Number  int
+	Body    string
+	Labels  []string
+	Removed string
+}
+
+// FakeIssueWriter is an in-memory IssueWriter that records the requests it
+// receives and fails them with Err when it is set.
+type FakeIssueWriter struct {
+	Err error
+
+	mu    sync.Mutex
+	calls []FakeIssueCall
+}
+
+func (f *FakeIssueWriter) AddComment(ctx context.Context, owner, repo string, number int, body string) error {
+	return f.record(ctx, FakeIssueCall{Owner: owner, Repo: repo, Number: number, Body: body})
+}
+
+func (f *FakeIssueWriter) AddLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	return f.record(ctx, FakeIssueCall{Owner: owner, Repo: repo, Number: number, Labels: labels})
+}
+
+func (f *FakeIssueWriter) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	return f.record(ctx, FakeIssueCall{Owner: owner, Repo: repo, Number: number, Removed: label})
+}
+
+func (f *FakeIssueWriter) record(ctx context.Context, call FakeIssueCall) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, call)
+	return f.Err
+}
+
+// Calls returns the requests received so far, in order.
+func (f *FakeIssueWriter) Calls() []FakeIssueCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeIssueCall(nil), f.calls...)
+}
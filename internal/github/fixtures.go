@@ -0,0 +1,153 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+// tokenPattern matches the documented GitHub token formats.
+var tokenPattern = regexp.MustCompile(`\b(gh[opsur]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+
+// Fixture is a single recorded GraphQL exchange.
+type Fixture struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	Response  json.RawMessage        `json:"response,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// recordingTransport passes requests through to next and writes every
+// exchange to dir as a numbered fixture file.
+type recordingTransport struct {
+	next    Transport
+	dir     string
+	secrets []string
+
+	mu  sync.Mutex
+	seq int
+}
+
+func newRecordingTransport(next Transport, dir string, secrets ...string) (*recordingTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+
+	existing, err := fixtureFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("record directory %s already contains %d fixtures; use an empty directory", dir, len(existing))
+	}
+
+	return &recordingTransport{next: next, dir: dir, secrets: secrets}, nil
+}
+
+func (t *recordingTransport) Execute(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
+	body, err := t.next.Execute(ctx, query, variables)
+
+	fixture := Fixture{Query: query, Variables: variables}
+	if err != nil {
+		fixture.Error = err.Error()
+	} else {
+		fixture.Response = json.RawMessage(body)
+	}
+	if writeErr := t.write(fixture); writeErr != nil {
+		return nil, writeErr
+	}
+
+	return body, err
+}
+
+func (t *recordingTransport) write(fixture Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	data = []byte(t.scrub(string(data)))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.seq++
+	path := filepath.Join(t.dir, fmt.Sprintf("%04d.json", t.seq))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// scrub removes credentials from a serialized fixture.
+func (t *recordingTransport) scrub(s string) string {
+	for _, secret := range t.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return tokenPattern.ReplaceAllString(s, redacted)
+}
+
+// NewReplayTransport returns a transport that serves the fixtures recorded in
+// dir, in recording order, without touching the network.
+func NewReplayTransport(dir string) (*FakeTransport, error) {
+	files, err := fixtureFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+
+	fake := NewFakeTransport()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", file, err)
+		}
+
+		if fixture.Error != "" {
+			fake.AddError(fixture.Query, fixture.Variables, errors.New(fixture.Error))
+		} else {
+			fake.Add(fixture.Query, fixture.Variables, string(fixture.Response))
+		}
+	}
+	return fake, nil
+}
+
+func fixtureFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "[0-9]*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fixtures: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ghHost is a host entry in the GitHub CLI's hosts.yml.
+type ghHost struct {
+	Name       string `yaml:"-"`
+	User       string `yaml:"user"`
+	OAuthToken string `yaml:"oauth_token"`
+}
+
+// ghConfigDir returns the GitHub CLI's configuration directory, located the
+// way gh itself locates it.
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return filepath.Join(dir, "GitHub CLI")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
+
+// readGHHosts returns the hosts the GitHub CLI is logged in to, in the order
+// hosts.yml lists them.
+func readGHHosts() ([]ghHost, error) {
+	dir := ghConfigDir()
+	if dir == "" {
+		return nil, fmt.Errorf("could not locate the GitHub CLI configuration directory")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse gh hosts.yml: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	// Decode entry by entry, as a map would lose the file's order
+	mapping := doc.Content[0]
+	var hosts []ghHost
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		host := ghHost{Name: strings.ToLower(mapping.Content[i].Value)}
+		if err := mapping.Content[i+1].Decode(&host); err != nil {
+			return nil, fmt.Errorf("failed to parse gh hosts.yml entry %q: %w", host.Name, err)
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// ghDefaultHost returns the host gh uses when none is given: the only host
+// it is logged in to, or "" when there are none or several.
+func ghDefaultHost() string {
+	hosts, err := readGHHosts()
+	if err != nil || len(hosts) != 1 {
+		return ""
+	}
+	return hosts[0].Name
+}
+
+// ghToken returns the token the GitHub CLI uses for host. Older gh versions
+// keep it in hosts.yml; newer ones keep it in the system keyring, which only
+// `gh auth token` can read.
+func ghToken(ctx context.Context, host string) (string, error) {
+	if hosts, err := readGHHosts(); err == nil {
+		for _, h := range hosts {
+			if h.Name == host && h.OAuthToken != "" {
+				return h.OAuthToken, nil
+			}
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "gh", "auth", "token", "--hostname", host)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("gh auth token --hostname %s failed: %w", host, err)
+	}
+
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("gh auth token --hostname %s returned no token", host)
+	}
+	return token, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultHost is the host of github.com.
+const DefaultHost = "github.com"
+
+// ResolveHost returns host, or when it is empty the host named by GH_HOST or
+// the only host the GitHub CLI is logged in to, falling back to github.com as
+// the GitHub CLI does.
+func ResolveHost(host string) string {
+	if host == "" {
+		host = os.Getenv("GH_HOST")
+	}
+	if host == "" {
+		host = ghDefaultHost()
+	}
+	if host == "" {
+		return DefaultHost
+	}
+	return strings.ToLower(host)
+}
+
+// IsEnterprise reports whether host is a GitHub Enterprise Server or
+// GHE.com host rather than github.com.
+func IsEnterprise(host string) bool {
+	host = strings.ToLower(host)
+	return host != DefaultHost && host != "api."+DefaultHost && !strings.HasSuffix(host, "."+DefaultHost)
+}
+
+// isTenancy reports whether host is a GHE.com host, which the GitHub CLI
+// treats like github.com in most respects.
+func isTenancy(host string) bool {
+	return strings.HasSuffix(strings.ToLower(host), ".ghe.com")
+}
+
+// GraphQLEndpoint returns the GraphQL API endpoint for host.
+func GraphQLEndpoint(host string) string {
+	switch {
+	case !IsEnterprise(host):
+		return "https://api.github.com/graphql"
+	case isTenancy(host):
+		return "https://api." + host + "/graphql"
+	default:
+		return "https://" + host + "/api/graphql"
+	}
+}
+
+// restBaseURL returns the REST API root for host, with a trailing slash.
+func restBaseURL(host string) string {
+	switch {
+	case !IsEnterprise(host):
+		return "https://api.github.com/"
+	case isTenancy(host):
+		return "https://api." + host + "/"
+	default:
+		return "https://" + host + "/api/v3/"
+	}
+}
+
+// tokenFromEnv returns the token the environment provides for host, using
+// the same variables as the GitHub CLI: the enterprise ones are only for
+// GitHub Enterprise Server, GHE.com hosts use those of github.com.
+func tokenFromEnv(host string) string {
+	names := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if IsEnterprise(host) && !isTenancy(host) {
+		names = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	for _, name := range names {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// ParseProjectURL splits a project URL such as
+// https://github.com/orgs/{owner}/projects/{number} into its host, owner and
+// project number. Any host is accepted, so GitHub Enterprise Server project
+// URLs work as well.
+func ParseProjectURL(projectURL string) (host, owner, number string, err error) {
+	raw := strings.TrimSpace(projectURL)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || !strings.Contains(u.Path, "/projects/") {
+		return "", "", "", fmt.Errorf("invalid GitHub project URL format: must be a URL with /projects/, e.g. https://github.com/orgs/{owner}/projects/{number}")
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, part := range parts {
+		if part == "projects" && i > 0 && i+1 < len(parts) && parts[i-1] != "" && parts[i+1] != "" {
+			return strings.ToLower(u.Host), parts[i-1], parts[i+1], nil
+		}
+	}
+
+	return "", "", "", fmt.Errorf("could not parse project URL: expected format https://%s/{owner}/projects/{number}", u.Host)
+}
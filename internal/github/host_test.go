@@ -0,0 +1,109 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProjectURL(t *testing.T) {
+	tests := []struct {
+		url                 string
+		host, owner, number string
+		wantErr             bool
+	}{
+		{url: "https://github.com/orgs/acme/projects/7", host: "github.com", owner: "acme", number: "7"},
+		{url: "https://github.com/users/octocat/projects/1/views/2", host: "github.com", owner: "octocat", number: "1"},
+		{url: "github.com/acme/projects/3/", host: "github.com", owner: "acme", number: "3"},
+		{url: "https://GHE.example.com/orgs/platform/projects/12", host: "ghe.example.com", owner: "platform", number: "12"},
+		{url: "https://ghe.example.com:8443/orgs/platform/projects/12", host: "ghe.example.com:8443", owner: "platform", number: "12"},
+		{url: "https://github.com/orgs/acme", wantErr: true},
+		{url: "https://github.com/orgs/acme/projects/", wantErr: true},
+		{url: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		host, owner, number, err := ParseProjectURL(tt.url)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseProjectURL(%q) = %s %s %s, want an error", tt.url, host, owner, number)
+			}
+			continue
+		}
+		if err != nil || host != tt.host || owner != tt.owner || number != tt.number {
+			t.Errorf("ParseProjectURL(%q) = %q %q %q, %v; want %q %q %q", tt.url, host, owner, number, err, tt.host, tt.owner, tt.number)
+		}
+	}
+}
+
+func TestGraphQLEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"github.com":      "https://api.github.com/graphql",
+		"ghe.example.com": "https://ghe.example.com/api/graphql",
+		"acme.ghe.com":    "https://api.acme.ghe.com/graphql",
+	}
+	for host, want := range tests {
+		if got := GraphQLEndpoint(host); got != want {
+			t.Errorf("GraphQLEndpoint(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestTokenFromEnv(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "dotcom-token")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "enterprise-token")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	if got := tokenFromEnv("github.com"); got != "dotcom-token" {
+		t.Errorf("github.com token = %q, want dotcom-token", got)
+	}
+	if got := tokenFromEnv("ghe.example.com"); got != "enterprise-token" {
+		t.Errorf("enterprise token = %q, want enterprise-token", got)
+	}
+	if got := tokenFromEnv("acme.ghe.com"); got != "dotcom-token" {
+		t.Errorf("GHE.com token = %q, want dotcom-token", got)
+	}
+}
+
+func TestGHConfigHosts(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	t.Setenv("GH_HOST", "")
+
+	hostsYAML := `ghe.example.com:
+    user: octocat
+    oauth_token: ghe-token
+    git_protocol: https
+`
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hostsYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ResolveHost(""); got != "ghe.example.com" {
+		t.Errorf("ResolveHost with a single gh host = %q, want ghe.example.com", got)
+	}
+	if got := ResolveHost("github.com"); got != "github.com" {
+		t.Errorf("ResolveHost(github.com) = %q, want the explicit host", got)
+	}
+
+	token, err := ghToken(context.Background(), "ghe.example.com")
+	if err != nil || token != "ghe-token" {
+		t.Errorf("ghToken = %q, %v; want ghe-token from hosts.yml", token, err)
+	}
+}
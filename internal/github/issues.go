@@ -0,0 +1,112 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// IssueWriter adds comments and labels to issues and pull requests through
+// the REST API.
+type IssueWriter interface {
+	AddComment(ctx context.Context, owner, repo string, number int, body string) error
+	AddLabels(ctx context.Context, owner, repo string, number int, labels []string) error
+	RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error
+}
+
+// restIssueWriter calls the REST API in process with the go-github client.
+type restIssueWriter struct {
+	client *github.Client
+}
+
+func (w *restIssueWriter) AddComment(ctx context.Context, owner, repo string, number int, body string) error {
+	_, _, err := w.client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: github.String(body)})
+	if err != nil {
+		return fmt.Errorf("failed to comment on %s/%s#%d: %w", owner, repo, number, err)
+	}
+	return nil
+}
+
+func (w *restIssueWriter) AddLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	_, _, err := w.client.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels)
+	if err != nil {
+		return fmt.Errorf("failed to label %s/%s#%d: %w", owner, repo, number, err)
+	}
+	return nil
+}
+
+func (w *restIssueWriter) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	_, err := w.client.Issues.RemoveLabelForIssue(ctx, owner, repo, number, label)
+	if err != nil {
+		return fmt.Errorf("failed to remove label %s from %s/%s#%d: %w", label, owner, repo, number, err)
+	}
+	return nil
+}
+
+// cliIssueWriter shells out to `gh api`, reusing the GitHub CLI's
+// authentication for host.
+type cliIssueWriter struct {
+	host string
+}
+
+func (w *cliIssueWriter) AddComment(ctx context.Context, owner, repo string, number int, body string) error {
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, number)
+	if err := w.request(ctx, "POST", path, "-f", "body="+body); err != nil {
+		return fmt.Errorf("failed to comment on %s/%s#%d: %w", owner, repo, number, err)
+	}
+	return nil
+}
+
+func (w *cliIssueWriter) AddLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/labels", owner, repo, number)
+	var fields []string
+	for _, label := range labels {
+		fields = append(fields, "-f", "labels[]="+label)
+	}
+	if err := w.request(ctx, "POST", path, fields...); err != nil {
+		return fmt.Errorf("failed to label %s/%s#%d: %w", owner, repo, number, err)
+	}
+	return nil
+}
+
+func (w *cliIssueWriter) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/labels/%s", owner, repo, number, url.PathEscape(label))
+	if err := w.request(ctx, "DELETE", path); err != nil {
+		return fmt.Errorf("failed to remove label %s from %s/%s#%d: %w", label, owner, repo, number, err)
+	}
+	return nil
+}
+
+func (w *cliIssueWriter) request(ctx context.Context, method, path string, fields ...string) error {
+	args := append([]string{"api", "--method", method, path}, fields...)
+	if w.host != "" {
+		args = append(args, "--hostname", w.host)
+	}
+
+	output, err := exec.CommandContext(ctx, "gh", args...).CombinedOutput()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("gh api %s: %s", path, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
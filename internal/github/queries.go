@@ -14,6 +14,11 @@
 
 package github
 
+import (
+	"fmt"
+	"strings"
+)
+
 const GetProjectQuery = `
 query($owner: String!, $number: Int!) {
   user(login: $owner) {
@@ -34,10 +39,14 @@ query($owner: String!, $number: Int!) {
 `
 
 const GetProjectFieldsQuery = `
-query($projectId: ID!) {
+query($projectId: ID!, $after: String) {
   node(id: $projectId) {
     ... on ProjectV2 {
-      fields(first: 100) {
+      fields(first: 100, after: $after) {
+        pageInfo {
+          hasNextPage
+          endCursor
+        }
         nodes {
           ... on ProjectV2Field {
             id
@@ -80,49 +89,205 @@ query($projectId: ID!, $after: String) {
           endCursor
         }
         nodes {
-          id
-          content {
-            ... on Issue {
-              id
-              number
-              title
-              state
-              repository {
-                name
-                owner {
-                  login
-                }
-              }
-            }
-          }
-          fieldValues(first: 20) {
-            nodes {
-              ... on ProjectV2ItemFieldIterationValue {
-                __typename
-                field {
-                  ... on ProjectV2IterationField {
-                    id
-                    name
-                  }
-                }
-                iterationId
-                title
-              }
-              ... on ProjectV2ItemFieldSingleSelectValue {
-                __typename
-                field {
-                  ... on ProjectV2SingleSelectField {
-                    id
-                    name
-                  }
-                }
-                name
-              }
-            }
-          }
+          ...IterationItem
+        }
+      }
+    }
+  }
+}
+` + iterationItemFragment + itemFieldValueFragment
+
+// GetFilteredIterationItemsQuery is GetIterationItemsQuery with the items
+// narrowed down by GitHub using the project filter syntax in $query, e.g.
+// sprint:"Sprint 23".
+const GetFilteredIterationItemsQuery = `
+query($projectId: ID!, $after: String, $query: String!) {
+  node(id: $projectId) {
+    ... on ProjectV2 {
+      items(first: 100, after: $after, query: $query) {
+        pageInfo {
+          hasNextPage
+          endCursor
+        }
+        nodes {
+          ...IterationItem
+        }
+      }
+    }
+  }
+}
+` + iterationItemFragment + itemFieldValueFragment
+
+// GetItemFieldValuesQuery fetches the field values of a project item past
+// the first page returned with the item.
+const GetItemFieldValuesQuery = `
+query($itemId: ID!, $after: String) {
+  node(id: $itemId) {
+    ... on ProjectV2Item {
+      fieldValues(first: 100, after: $after) {
+        pageInfo {
+          hasNextPage
+          endCursor
+        }
+        nodes {
+          ...ItemFieldValue
+        }
+      }
+    }
+  }
+}
+` + itemFieldValueFragment
+
+// GetViewerQuery fetches the login of the authenticated user.
+const GetViewerQuery = `
+query {
+  viewer {
+    login
+  }
+}
+`
+
+// GetItemIterationsQuery fetches the value of the iteration field named
+// $field, and when the item was last changed, for up to 100 project items.
+const GetItemIterationsQuery = `
+query($ids: [ID!]!, $field: String!) {
+  nodes(ids: $ids) {
+    ... on ProjectV2Item {
+      id
+      updatedAt
+      fieldValueByName(name: $field) {
+        ... on ProjectV2ItemFieldIterationValue {
+          iterationId
+          title
+        }
+      }
+    }
+  }
+}
+`
+
+const iterationItemFragment = `
+fragment IterationItem on ProjectV2Item {
+  id
+  updatedAt
+  content {
+    __typename
+    ... on Issue {
+      id
+      number
+      title
+      state
+      closedAt
+      repository {
+        name
+        owner {
+          login
+        }
+      }
+      labels(first: 50) {
+        pageInfo {
+          hasNextPage
+        }
+        nodes {
+          name
+        }
+      }
+      assignees(first: 20) {
+        pageInfo {
+          hasNextPage
+        }
+        nodes {
+          login
+        }
+      }
+    }
+    ... on PullRequest {
+      id
+      number
+      title
+      state
+      merged
+      closedAt
+      repository {
+        name
+        owner {
+          login
+        }
+      }
+      labels(first: 50) {
+        pageInfo {
+          hasNextPage
         }
+        nodes {
+          name
+        }
+      }
+      assignees(first: 20) {
+        pageInfo {
+          hasNextPage
+        }
+        nodes {
+          login
+        }
+      }
+    }
+    ... on DraftIssue {
+      id
+      title
+      assignees(first: 20) {
+        pageInfo {
+          hasNextPage
+        }
+        nodes {
+          login
+        }
+      }
+    }
+  }
+  fieldValues(first: 50) {
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
+    nodes {
+      ...ItemFieldValue
+    }
+  }
+}
+`
+
+const itemFieldValueFragment = `
+fragment ItemFieldValue on ProjectV2ItemFieldValue {
+  ... on ProjectV2ItemFieldIterationValue {
+    __typename
+    field {
+      ... on ProjectV2IterationField {
+        id
+        name
+      }
+    }
+    iterationId
+    title
+  }
+  ... on ProjectV2ItemFieldSingleSelectValue {
+    __typename
+    field {
+      ... on ProjectV2SingleSelectField {
+        id
+        name
       }
     }
+    name
+  }
+  ... on ProjectV2ItemFieldNumberValue {
+    __typename
+    field {
+      ... on ProjectV2Field {
+        id
+        name
+      }
+    }
+    number
   }
 }
 `
@@ -142,4 +307,50 @@ mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $iterationId: String!) {
     }
   }
 }
-`
\ No newline at end of file
+`
+
+// UpdateItemNumberMutation sets a number field of a project item.
+const UpdateItemNumberMutation = `
+mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $number: Float!) {
+  updateProjectV2ItemFieldValue(input: {
+    projectId: $projectId
+    itemId: $itemId
+    fieldId: $fieldId
+    value: {
+      number: $number
+    }
+  }) {
+    projectV2Item {
+      id
+    }
+  }
+}
+`
+
+// UpdateItemIterationsMutation returns a mutation that sets the iteration of
+// count items in a single request. Each update is aliased u0, u1, ... and
+// takes its item and iteration from the variables $item0, $iteration0, ...;
+// $projectId and $fieldId are shared.
+func UpdateItemIterationsMutation(count int) string {
+	var params, body strings.Builder
+	params.WriteString("$projectId: ID!, $fieldId: ID!")
+
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&params, ", $item%d: ID!, $iteration%d: String!", i, i)
+		fmt.Fprintf(&body, `
+  u%d: updateProjectV2ItemFieldValue(input: {
+    projectId: $projectId
+    itemId: $item%d
+    fieldId: $fieldId
+    value: {
+      iterationId: $iteration%d
+    }
+  }) {
+    projectV2Item {
+      id
+    }
+  }`, i, i, i)
+	}
+
+	return fmt.Sprintf("\nmutation(%s) {%s\n}\n", params.String(), body.String())
+}
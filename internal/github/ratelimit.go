@@ -0,0 +1,345 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorClass categorises a failed GitHub API request.
+type ErrorClass int
+
+const (
+	ClassUnknown ErrorClass = iota
+	ClassAuth
+	ClassNotFound
+	ClassRateLimited
+	ClassTransient
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ClassAuth:
+		return "authentication failed"
+	case ClassNotFound:
+		return "not found"
+	case ClassRateLimited:
+		return "rate limited"
+	case ClassTransient:
+		return "temporarily unavailable"
+	default:
+		return "request failed"
+	}
+}
+
+// APIError is a GitHub API request that failed before a usable GraphQL
+// response was returned.
+type APIError struct {
+	Class      ErrorClass
+	StatusCode int
+	Message    string
+	// RetryAfter is how long GitHub asked us to wait before trying again, or
+	// zero when it did not say.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := "GitHub API " + e.Class.String()
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (HTTP %d)", e.StatusCode)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Retryable reports whether the request may succeed if sent again.
+func (e *APIError) Retryable() bool {
+	return e.Class == ClassRateLimited || e.Class == ClassTransient
+}
+
+// RateLimit is the GraphQL request budget GitHub reported in its most recent
+// response.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Used      int
+	Reset     time.Time
+}
+
+// parseRateLimit reads the X-RateLimit-* headers of a response.
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	limit := RateLimit{Remaining: remaining}
+	limit.Limit, _ = strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	limit.Used, _ = strconv.Atoi(header.Get("X-RateLimit-Used"))
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		limit.Reset = time.Unix(reset, 0)
+	}
+	return limit, true
+}
+
+// classifyResponse returns an *APIError for responses that carry no usable
+// GraphQL result, or nil when the body should be decoded.
+func classifyResponse(resp *http.Response, body []byte, now time.Time) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    responseMessage(body),
+		RetryAfter: retryAfter(resp.Header, now),
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if graphQLRateLimited(body) {
+			apiErr.Class = ClassRateLimited
+			return apiErr
+		}
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		apiErr.Class = ClassAuth
+	case resp.StatusCode == http.StatusTooManyRequests:
+		apiErr.Class = ClassRateLimited
+	case resp.StatusCode == http.StatusForbidden:
+		// GitHub answers 403 both for missing permissions and for secondary
+		// rate limits; only the latter come with a wait hint or say so.
+		if apiErr.RetryAfter > 0 || resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+			strings.Contains(strings.ToLower(apiErr.Message), "rate limit") {
+			apiErr.Class = ClassRateLimited
+		} else {
+			apiErr.Class = ClassAuth
+		}
+	case resp.StatusCode == http.StatusNotFound:
+		apiErr.Class = ClassNotFound
+	case resp.StatusCode >= 500:
+		apiErr.Class = ClassTransient
+	default:
+		apiErr.Class = ClassUnknown
+	}
+	return apiErr
+}
+
+// retryAfter returns how long the response asks clients to wait, from
+// Retry-After or, when the budget is spent, X-RateLimit-Reset.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(value); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+	if limit, ok := parseRateLimit(header); ok && limit.Remaining == 0 && limit.Reset.After(now) {
+		return limit.Reset.Sub(now)
+	}
+	return 0
+}
+
+// responseMessage extracts a short description from an error response body.
+func responseMessage(body []byte) string {
+	var result struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil {
+		if result.Message != "" {
+			return result.Message
+		}
+		if len(result.Errors) > 0 {
+			return result.Errors[0].Message
+		}
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > 200 {
+		msg = msg[:200] + "..."
+	}
+	return msg
+}
+
+// graphQLRateLimited reports whether a GraphQL response was rejected because
+// the primary rate limit was exhausted, which GitHub signals with HTTP 200.
+func graphQLRateLimited(body []byte) bool {
+	var result struct {
+		Errors []struct {
+			Type string `json:"type"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return false
+	}
+	for _, gqlErr := range result.Errors {
+		if gqlErr.Type == "RATE_LIMITED" {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyCLIError maps the stderr of a failed `gh api` call to an error
+// class; gh reports the HTTP status as "(HTTP 502)".
+func classifyCLIError(stderr string) ErrorClass {
+	lower := strings.ToLower(stderr)
+	switch {
+	case strings.Contains(lower, "rate limit"), strings.Contains(stderr, "HTTP 429"):
+		return ClassRateLimited
+	case strings.Contains(stderr, "HTTP 401"), strings.Contains(stderr, "HTTP 403"):
+		return ClassAuth
+	case strings.Contains(stderr, "HTTP 404"):
+		return ClassNotFound
+	case strings.Contains(stderr, "HTTP 5"), strings.Contains(lower, "timeout"), strings.Contains(lower, "connection reset"):
+		return ClassTransient
+	default:
+		return ClassUnknown
+	}
+}
+
+// rateLimitState holds the most recent RateLimit seen by a transport.
+type rateLimitState struct {
+	mu    sync.Mutex
+	limit RateLimit
+	known bool
+}
+
+func (s *rateLimitState) update(limit RateLimit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	s.known = true
+}
+
+func (s *rateLimitState) get() (RateLimit, bool) {
+	if s == nil {
+		return RateLimit{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limit, s.known
+}
+
+// retryTransport retries rate limited and transient failures with jittered
+// exponential backoff, and pauses until the budget resets when the last
+// response reported none left.
+type retryTransport struct {
+	next        Transport
+	limits      *rateLimitState
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+
+	sleep func(context.Context, time.Duration) error
+	now   func() time.Time
+	rand  func(int64) int64
+}
+
+func newRetryTransport(next Transport, limits *rateLimitState) *retryTransport {
+	return &retryTransport{
+		next:        next,
+		limits:      limits,
+		maxAttempts: 5,
+		baseDelay:   time.Second,
+		maxDelay:    time.Minute,
+		sleep:       sleepContext,
+		now:         time.Now,
+		rand:        rand.Int63n,
+	}
+}
+
+func (t *retryTransport) Execute(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		if err := t.waitForBudget(ctx); err != nil {
+			return nil, err
+		}
+
+		body, err := t.next.Execute(ctx, query, variables)
+
+		var apiErr *APIError
+		if err == nil || !errors.As(err, &apiErr) || !apiErr.Retryable() || attempt >= t.maxAttempts {
+			return body, err
+		}
+
+		delay := t.backoff(attempt, apiErr)
+		log.Printf("%v; retrying in %s (attempt %d of %d)", err, delay.Round(time.Millisecond), attempt+1, t.maxAttempts)
+		if err := t.sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("gave up retrying: %w (last error: %v)", err, apiErr)
+		}
+	}
+}
+
+// backoff returns the delay before retrying after the given attempt: the
+// server's hint when it gave one, otherwise an exponentially growing delay
+// with full jitter between half and all of it.
+func (t *retryTransport) backoff(attempt int, apiErr *APIError) time.Duration {
+	if apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	delay := t.baseDelay << (attempt - 1)
+	if delay > t.maxDelay || delay <= 0 {
+		delay = t.maxDelay
+	}
+	half := delay / 2
+	return half + time.Duration(t.rand(int64(half)+1))
+}
+
+func (t *retryTransport) waitForBudget(ctx context.Context) error {
+	limit, ok := t.limits.get()
+	if !ok || limit.Remaining > 0 {
+		return nil
+	}
+
+	now := t.now()
+	if !limit.Reset.After(now) {
+		return nil
+	}
+
+	wait := limit.Reset.Sub(now) + time.Second
+	log.Printf("GitHub API rate limit exhausted (%d requests per hour); pausing until %s", limit.Limit, limit.Reset.Format("15:04:05"))
+	if err := t.sleep(ctx, wait); err != nil {
+		return fmt.Errorf("stopped waiting for the rate limit to reset: %w", err)
+	}
+	return nil
+}
+
+// sleepContext waits for d, returning early with the context's error when it
+// is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// scriptedServer answers successive requests with the given handlers.
+func scriptedServer(t *testing.T, handlers ...http.HandlerFunc) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls >= len(handlers) {
+			t.Errorf("unexpected request %d", calls+1)
+			http.Error(w, "unexpected", http.StatusInternalServerError)
+			return
+		}
+		handlers[calls](w, r)
+		calls++
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestRetryTransport(url string) (*retryTransport, *[]time.Duration) {
+	limits := &rateLimitState{}
+	transport := newRetryTransport(&httpTransport{client: http.DefaultClient, endpoint: url, limits: limits}, limits)
+	var slept []time.Duration
+	transport.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	transport.rand = func(n int64) int64 { return n - 1 }
+	return transport, &slept
+}
+
+func respondOK(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("X-RateLimit-Limit", "5000")
+	w.Header().Set("X-RateLimit-Remaining", "4999")
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	fmt.Fprint(w, `{"data":{"viewer":{"login":"octocat"}}}`)
+}
+
+func TestRetryTransportRetriesTransientErrors(t *testing.T) {
+	server, calls := scriptedServer(t,
+		func(w http.ResponseWriter, _ *http.Request) { http.Error(w, "bad gateway", http.StatusBadGateway) },
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit."}`)
+		},
+		respondOK,
+	)
+	transport, slept := newTestRetryTransport(server.URL)
+
+	if _, err := transport.Execute(context.Background(), "query { viewer { login } }", nil); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("got %d requests, want 3", *calls)
+	}
+	if want := []time.Duration{time.Second, 7 * time.Second}; fmt.Sprint(*slept) != fmt.Sprint(want) {
+		t.Errorf("slept %v, want %v", *slept, want)
+	}
+
+	limit, known := transport.limits.get()
+	if !known || limit.Remaining != 4999 || limit.Limit != 5000 {
+		t.Errorf("rate limit = %+v (known %v), want 4999 of 5000 remaining", limit, known)
+	}
+}
+
+func TestRetryTransportDoesNotRetryAuthErrors(t *testing.T) {
+	server, calls := scriptedServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"Bad credentials"}`)
+	})
+	transport, slept := newTestRetryTransport(server.URL)
+
+	_, err := transport.Execute(context.Background(), "query { viewer { login } }", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Class != ClassAuth {
+		t.Fatalf("got error %v, want an authentication APIError", err)
+	}
+	if *calls != 1 || len(*slept) != 0 {
+		t.Errorf("got %d requests and %d sleeps, want 1 and 0", *calls, len(*slept))
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	var handlers []http.HandlerFunc
+	for i := 0; i < 5; i++ {
+		handlers = append(handlers, func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		})
+	}
+	server, calls := scriptedServer(t, handlers...)
+	transport, slept := newTestRetryTransport(server.URL)
+
+	_, err := transport.Execute(context.Background(), "query { viewer { login } }", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Class != ClassTransient {
+		t.Fatalf("got error %v, want a transient APIError", err)
+	}
+	if *calls != 5 {
+		t.Errorf("got %d requests, want 5", *calls)
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}; fmt.Sprint(*slept) != fmt.Sprint(want) {
+		t.Errorf("slept %v, want %v", *slept, want)
+	}
+}
+
+func TestRetryTransportPausesWhenBudgetIsSpent(t *testing.T) {
+	reset := time.Now().Add(30 * time.Second)
+	server, calls := scriptedServer(t,
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			fmt.Fprint(w, `{"data":{"viewer":{"login":"octocat"}}}`)
+		},
+		respondOK,
+	)
+	transport, slept := newTestRetryTransport(server.URL)
+
+	for i := 0; i < 2; i++ {
+		if _, err := transport.Execute(context.Background(), "query { viewer { login } }", nil); err != nil {
+			t.Fatalf("Execute %d: %v", i, err)
+		}
+	}
+	if *calls != 2 {
+		t.Errorf("got %d requests, want 2", *calls)
+	}
+	if len(*slept) != 1 || (*slept)[0] < 20*time.Second {
+		t.Errorf("slept %v, want one pause until the reset", *slept)
+	}
+}
+
+func TestClassifyGraphQLRateLimit(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	body := []byte(`{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`)
+
+	apiErr := classifyResponse(resp, body, time.Now())
+	if apiErr == nil || apiErr.Class != ClassRateLimited || apiErr.Message != "API rate limit exceeded" {
+		t.Errorf("classifyResponse = %v, want a rate limit error", apiErr)
+	}
+}
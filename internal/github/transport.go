@@ -0,0 +1,157 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Transport sends a single GraphQL request over the wire and returns the raw
+// response body.
+type Transport interface {
+	Execute(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error)
+}
+
+// GraphQLDoer runs GraphQL requests and returns the raw response body. It is
+// the dependency used by higher level packages; *Client implements it on top
+// of a Transport.
+type GraphQLDoer interface {
+	DoGraphQL(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error)
+}
+
+// graphQLRequest is the body of a GraphQL request.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// httpTransport posts requests to the GraphQL endpoint with an authenticated
+// HTTP client. Failed responses are returned as *APIError, and the rate limit
+// reported by each response is recorded in limits when it is set.
+type httpTransport struct {
+	client   *http.Client
+	endpoint string
+	limits   *rateLimitState
+}
+
+func (t *httpTransport) Execute(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
+	reqBody, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GraphQL request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("GraphQL request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GraphQL response: %w", err)
+	}
+
+	if limit, ok := parseRateLimit(resp.Header); ok && t.limits != nil {
+		t.limits.update(limit)
+	}
+	if apiErr := classifyResponse(resp, body, time.Now()); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return body, nil
+}
+
+// cliTransport shells out to `gh api graphql`, reusing the GitHub CLI's
+// authentication for host.
+type cliTransport struct {
+	host string
+}
+
+// request returns the gh arguments and standard input for a GraphQL request.
+// The request body goes in whole through --input, since gh's -f and -F
+// fields would turn lists into a single string and send fractional numbers
+// as strings.
+func (t *cliTransport) request(query string, variables map[string]interface{}) ([]string, []byte, error) {
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	args := []string{"api", "graphql", "--input", "-"}
+	if t.host != "" {
+		args = append(args, "--hostname", t.host)
+	}
+	return args, body, nil
+}
+
+func (t *cliTransport) Execute(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
+	args, body, err := t.request(query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Stdin = bytes.NewReader(body)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("GraphQL query cancelled: %w", ctxErr)
+	}
+
+	// gh exits non-zero for GraphQL errors as well, so any JSON document on
+	// stdout is handed to the client's response policy
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors json.RawMessage `json:"errors"`
+	}
+	parseErr := json.Unmarshal(stdout.Bytes(), &result)
+	hasData := len(result.Data) > 0 && string(result.Data) != "null"
+	hasErrors := len(result.Errors) > 0 && string(result.Errors) != "null"
+
+	if parseErr != nil || (!hasData && !hasErrors) {
+		if err != nil {
+			if class := classifyCLIError(stderr.String()); class != ClassUnknown {
+				return nil, &APIError{Class: class, Message: strings.TrimSpace(stderr.String())}
+			}
+			return nil, fmt.Errorf("GraphQL query failed: %v\nStderr: %s\nStdout: %s", err, stderr.String(), stdout.String())
+		}
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse GraphQL response: %w\nOutput: %s", parseErr, stdout.String())
+		}
+	}
+
+	if !hasData && graphQLRateLimited(stdout.Bytes()) {
+		return nil, &APIError{Class: ClassRateLimited, Message: responseMessage(stdout.Bytes())}
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestCLITransportRequest(t *testing.T) {
+	transport := &cliTransport{host: "ghe.example.com"}
+	args, body, err := transport.request(GetItemIterationsQuery, map[string]interface{}{
+		"ids":    []string{"PVTI_a", "PVTI_b"},
+		"field":  "Sprint",
+		"number": 2.5,
+	})
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+
+	want := []string{"api", "graphql", "--input", "-", "--hostname", "ghe.example.com"}
+	if fmt.Sprint(args) != fmt.Sprint(want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+
+	var sent struct {
+		Query     string
+		Variables struct {
+			IDs    []string `json:"ids"`
+			Field  string   `json:"field"`
+			Number float64  `json:"number"`
+		}
+	}
+	if err := json.Unmarshal(body, &sent); err != nil {
+		t.Fatalf("stdin is not a JSON request: %v\n%s", err, body)
+	}
+	if sent.Query != GetItemIterationsQuery {
+		t.Errorf("query = %q, want GetItemIterationsQuery", sent.Query)
+	}
+	if fmt.Sprint(sent.Variables.IDs) != "[PVTI_a PVTI_b]" || sent.Variables.Field != "Sprint" || sent.Variables.Number != 2.5 {
+		t.Errorf("variables = %+v, want the list, string and number unchanged", sent.Variables)
+	}
+}
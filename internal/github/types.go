@@ -14,7 +14,11 @@
 
 package github
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type Project struct {
 	ID     string
@@ -34,22 +38,101 @@ type Iteration struct {
 		ID   string
 		Name string
 	}
+	// Completed is set for iterations listed under completedIterations.
+	Completed bool
+
+	// invalid records why the iteration could not be decoded.
+	invalid error
+}
+
+// Invalid returns why the iteration could not be decoded, or nil. An invalid
+// iteration has its ID and title but no dates.
+func (i *Iteration) Invalid() error {
+	return i.invalid
+}
+
+// EndDate returns when the iteration is over: midnight after its last day.
+func (i *Iteration) EndDate() time.Time {
+	return i.StartDate.AddDate(0, 0, i.Duration)
 }
 
+// UnmarshalJSON decodes an iteration as returned by the GraphQL API, where
+// startDate is a plain YYYY-MM-DD date rather than an RFC 3339 timestamp. A
+// startDate that cannot be parsed marks the iteration Invalid rather than
+// failing the whole response.
+func (i *Iteration) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        string
+		Title     string
+		StartDate string
+		Duration  int
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	i.ID = raw.ID
+	i.Title = raw.Title
+	startDate, err := time.Parse("2006-01-02", raw.StartDate)
+	if err != nil {
+		i.invalid = fmt.Errorf("invalid startDate %q: %w", raw.StartDate, err)
+		return nil
+	}
+	i.StartDate = startDate
+	i.Duration = raw.Duration
+	return nil
+}
+
+// ItemKind is the type of content behind a project item, as reported by the
+// GraphQL __typename.
+type ItemKind string
+
+const (
+	KindIssue       ItemKind = "Issue"
+	KindPullRequest ItemKind = "PullRequest"
+	KindDraftIssue  ItemKind = "DraftIssue"
+)
+
+// Issue is the content of a project item: an issue, a pull request or a draft
+// issue, as told by Kind. Draft issues have no number, state, closing time or
+// repository.
 type Issue struct {
 	ID         string
+	Kind       ItemKind `json:"__typename"`
 	Number     int
 	Title      string
 	State      string
+	Merged     bool
+	ClosedAt   time.Time
 	Repository struct {
 		Name  string
 		Owner struct {
 			Login string
 		}
 	}
+	Labels struct {
+		PageInfo PageInfo
+		Nodes    []struct {
+			Name string
+		}
+	}
+	Assignees struct {
+		PageInfo PageInfo
+		Nodes    []struct {
+			Login string
+		}
+	}
+	// IterationID and IterationTitle identify the iteration the item was
+	// fetched from.
+	IterationID    string `json:"-"`
+	IterationTitle string `json:"-"`
+	// Carryovers is how many times the item has been rolled over, when that
+	// is tracked.
+	Carryovers   int `json:"-"`
 	ProjectItems struct {
 		Nodes []struct {
-			ID         string
+			ID          string
+			UpdatedAt   time.Time
 			FieldValues struct {
 				Nodes []FieldValue
 			}
@@ -57,13 +140,126 @@ type Issue struct {
 	}
 }
 
+// Ref returns a short human reference to the item, such as "#12", "PR #12"
+// or "draft".
+func (i *Issue) Ref() string {
+	switch i.Kind {
+	case KindPullRequest:
+		return fmt.Sprintf("PR #%d", i.Number)
+	case KindDraftIssue:
+		return "draft"
+	default:
+		return fmt.Sprintf("#%d", i.Number)
+	}
+}
+
 type FieldValue struct {
 	TypeName string `json:"__typename"`
 	Field    struct {
 		ID   string
 		Name string
 	}
-	Title    string
-	Duration int
-	ID       string
-}
\ No newline at end of file
+	Title       string
+	Name        string
+	IterationID string
+	Number      float64
+	Duration    int
+	ID          string
+}
+
+type PageInfo struct {
+	HasNextPage bool
+	EndCursor   string
+}
+
+// ProjectField is a field definition as returned by GetProjectFieldsQuery.
+// Configuration is only set for iteration fields.
+type ProjectField struct {
+	ID            string
+	Name          string
+	DataType      string
+	Configuration *struct {
+		Iterations          []*Iteration
+		CompletedIterations []*Iteration
+	}
+}
+
+// ProjectItem is a project item as returned by GetIterationItemsQuery.
+type ProjectItem struct {
+	ID          string
+	UpdatedAt   time.Time
+	Content     *Issue
+	FieldValues struct {
+		PageInfo PageInfo
+		Nodes    []FieldValue
+	}
+}
+
+// ProjectResponse is the data returned by GetProjectQuery.
+type ProjectResponse struct {
+	User *struct {
+		ProjectV2 *Project
+	}
+	Organization *struct {
+		ProjectV2 *Project
+	}
+}
+
+// ProjectFieldsResponse is the data returned by GetProjectFieldsQuery.
+type ProjectFieldsResponse struct {
+	Node *struct {
+		Fields *struct {
+			PageInfo PageInfo
+			Nodes    []ProjectField
+		}
+	}
+}
+
+// ProjectItemsResponse is the data returned by GetIterationItemsQuery.
+type ProjectItemsResponse struct {
+	Node *struct {
+		Items *struct {
+			PageInfo PageInfo
+			Nodes    []ProjectItem
+		}
+	}
+}
+
+// ItemFieldValuesResponse is the data returned by GetItemFieldValuesQuery.
+type ItemFieldValuesResponse struct {
+	Node *struct {
+		FieldValues *struct {
+			PageInfo PageInfo
+			Nodes    []FieldValue
+		}
+	}
+}
+
+// ViewerResponse is the data returned by GetViewerQuery.
+type ViewerResponse struct {
+	Viewer *struct {
+		Login string
+	}
+}
+
+// ItemIterationsResponse is the data returned by GetItemIterationsQuery. Items
+// that no longer exist come back as nil nodes.
+type ItemIterationsResponse struct {
+	Nodes []*struct {
+		ID               string
+		UpdatedAt        time.Time
+		FieldValueByName *struct {
+			IterationID string
+			Title       string
+		}
+	}
+}
+
+// UpdateItemFieldResponse is the data returned by UpdateItemIterationMutation.
+type UpdateItemFieldResponse struct {
+	UpdateProjectV2ItemFieldValue *struct {
+		ProjectV2Item *struct {
+			ID string
+		}
+	}
+}
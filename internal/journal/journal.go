@@ -0,0 +1,382 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package journal records the iteration changes a command makes so that they
+// can be reviewed and reverted later.
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version is the journal format written by this package.
+const Version = 1
+
+// Commands that write journals.
+const (
+	CommandRollover = "rollover"
+	CommandApply    = "apply"
+	CommandUndo     = "undo"
+)
+
+// Journal records the iteration changes made by one command run.
+type Journal struct {
+	Version   int       `json:"version"`
+	Command   string    `json:"command"`
+	Project   Project   `json:"project"`
+	Field     Field     `json:"field"`
+	Actor     string    `json:"actor,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	// Undoes is the journal an undo run reverted.
+	Undoes string `json:"undoes,omitempty"`
+	// Skipped lists the items of the Undoes journal an undo run left alone,
+	// since they had changed since and there was nothing to put back.
+	Skipped []string `json:"skipped,omitempty"`
+	Entries []Entry  `json:"entries"`
+}
+
+// Project identifies the project a journal applies to.
+type Project struct {
+	URL string `json:"url"`
+	ID  string `json:"id"`
+}
+
+// Field identifies the iteration field that was changed.
+type Field struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Entry is a single attempt to move an item between iterations. Error is set
+// when GitHub rejected the change. Pending is set while the change is being
+// made, so an entry still pending was left by a run that stopped before it
+// learnt whether the change went through.
+type Entry struct {
+	ItemID          string    `json:"itemId"`
+	Ref             string    `json:"ref,omitempty"`
+	Title           string    `json:"title,omitempty"`
+	FromIterationID string    `json:"fromIterationId"`
+	FromIteration   string    `json:"fromIteration,omitempty"`
+	ToIterationID   string    `json:"toIterationId"`
+	ToIteration     string    `json:"toIteration,omitempty"`
+	Time            time.Time `json:"time"`
+	Error           string    `json:"error,omitempty"`
+	Pending         bool      `json:"pending,omitempty"`
+	// Carryover is the carryover count raised along with the move, if any.
+	Carryover *Carryover `json:"carryover,omitempty"`
+}
+
+// Carryover is a change to the carryover count of an item. The count is kept
+// in the number field FieldID, or in a carryover label on issue Number of
+// Owner/Repo when FieldID is empty.
+type Carryover struct {
+	FieldID string `json:"fieldId,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+	Repo    string `json:"repo,omitempty"`
+	Number  int    `json:"number,omitempty"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+}
+
+// Reverse returns the change that puts the count back to c.From.
+func (c *Carryover) Reverse() *Carryover {
+	reverse := *c
+	reverse.From, reverse.To = c.To, c.From
+	return &reverse
+}
+
+// Applied reports whether the change recorded by e was made.
+func (e Entry) Applied() bool {
+	return e.Error == "" && !e.Pending
+}
+
+// New returns an empty journal for a run of command started now.
+func New(command string, project Project, field Field, actor string) *Journal {
+	return &Journal{
+		Version:   Version,
+		Command:   command,
+		Project:   project,
+		Field:     field,
+		Actor:     actor,
+		StartedAt: time.Now().UTC(),
+		Entries:   []Entry{},
+	}
+}
+
+// Record appends entry, stamping it with the current time if it has none.
+func (j *Journal) Record(entry Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	j.Entries = append(j.Entries, entry)
+}
+
+// Applied returns the entries whose change was made.
+func (j *Journal) Applied() []Entry {
+	var applied []Entry
+	for _, entry := range j.Entries {
+		if entry.Applied() {
+			applied = append(applied, entry)
+		}
+	}
+	return applied
+}
+
+// Undoable returns the entries whose change was made or may have been, which
+// an undo has to look at.
+func (j *Journal) Undoable() []Entry {
+	var undoable []Entry
+	for _, entry := range j.Entries {
+		if entry.Error == "" {
+			undoable = append(undoable, entry)
+		}
+	}
+	return undoable
+}
+
+// Dir returns the directory journals are kept in by default,
+// $XDG_STATE_HOME/gh-projects/journal.
+func Dir() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find the journal directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "gh-projects", "journal"), nil
+}
+
+// Save writes the journal to path, or to a new file named after the start
+// time and command in Dir when path is empty, and returns the path written.
+func (j *Journal) Save(path string) (string, error) {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode journal: %w", err)
+	}
+	data = append(data, '\n')
+
+	if path != "" {
+		// Journals are rewritten while their changes are made, so the old
+		// copy is replaced whole rather than left half written
+		temp := path + ".tmp"
+		if err := os.WriteFile(temp, data, 0o644); err != nil {
+			return "", fmt.Errorf("failed to write journal: %w", err)
+		}
+		if err := os.Rename(temp, path); err != nil {
+			os.Remove(temp)
+			return "", fmt.Errorf("failed to write journal: %w", err)
+		}
+		return path, nil
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	// Runs started within the same second get a numbered suffix
+	name := j.StartedAt.UTC().Format("20060102T150405Z") + "-" + j.Command
+	for n := 1; ; n++ {
+		path = filepath.Join(dir, name+".json")
+		if n > 1 {
+			path = filepath.Join(dir, fmt.Sprintf("%s-%d.json", name, n))
+		}
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write journal: %w", err)
+		}
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			return "", fmt.Errorf("failed to write journal: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			return "", fmt.Errorf("failed to write journal: %w", err)
+		}
+		return path, nil
+	}
+}
+
+// Load reads the journal at path.
+func Load(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("invalid journal %s: %w", path, err)
+	}
+	if j.Version != Version {
+		return nil, fmt.Errorf("journal %s has unsupported version %d", path, j.Version)
+	}
+	return &j, nil
+}
+
+// Latest returns the path of the most recent journal in dir that can still be
+// undone: one written by a command other than undo with changes that undo
+// runs have not yet restored or skipped. When projectURL is set only journals
+// for that project count.
+func Latest(dir, projectURL string) (string, error) {
+	paths, err := standing(dir, projectURL)
+	if err != nil {
+		return "", err
+	}
+	if len(paths) > 0 {
+		return paths[len(paths)-1], nil
+	}
+
+	if projectURL != "" {
+		return "", fmt.Errorf("no journal to undo for %s in %s", projectURL, dir)
+	}
+	return "", fmt.Errorf("no journal to undo in %s", dir)
+}
+
+// Standing returns the journals in dir written by commands other than undo,
+// oldest first, holding only the entries whose changes still stand: the ones
+// undo runs restored are left out. When projectURL is set only journals for
+// that project count.
+func Standing(dir, projectURL string) ([]*Journal, error) {
+	paths, journals, undos, err := load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Journal
+	for _, path := range paths {
+		j, ok := journals[path]
+		if !ok || j.Command == CommandUndo {
+			continue
+		}
+		if projectURL != "" && !j.IsFor(projectURL) {
+			continue
+		}
+		if u, ok := undos[filepath.Clean(path)]; ok {
+			entries := make([]Entry, 0, len(j.Entries))
+			for _, entry := range j.Entries {
+				if !u.restored[entry.ItemID] {
+					entries = append(entries, entry)
+				}
+			}
+			j.Entries = entries
+		}
+		result = append(result, j)
+	}
+	return result, nil
+}
+
+// undone holds the items of a journal that undo runs restored or skipped.
+type undone struct {
+	restored map[string]bool
+	skipped  map[string]bool
+}
+
+// covers reports whether undo runs restored or skipped every change in j
+// that can be undone.
+func (u *undone) covers(j *Journal) bool {
+	for _, entry := range j.Undoable() {
+		if !u.restored[entry.ItemID] && !u.skipped[entry.ItemID] {
+			return false
+		}
+	}
+	return true
+}
+
+// standing returns the paths of the journals in dir that have not been
+// undone, oldest first. A journal is undone once
+// undo runs have restored or skipped every change it can undo, so an undo
+// that was interrupted or failed part way leaves it standing.
+func standing(dir, projectURL string) ([]string, error) {
+	paths, journals, undos, err := load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []string
+	for _, path := range paths {
+		j, ok := journals[path]
+		if !ok || j.Command == CommandUndo {
+			continue
+		}
+		if projectURL != "" && !j.IsFor(projectURL) {
+			continue
+		}
+		if u, ok := undos[filepath.Clean(path)]; !ok || !u.covers(j) {
+			kept = append(kept, path)
+		}
+	}
+	return kept, nil
+}
+
+// load reads the journals in dir, oldest first, along with what the undo
+// journals among them record about the journals they undo, keyed by path.
+// Journals that cannot be read are left out.
+func load(dir string) ([]string, map[string]*Journal, map[string]*undone, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list journals: %w", err)
+	}
+	// Names start with the UTC start time, so they sort chronologically
+	sort.Strings(paths)
+
+	journals := make(map[string]*Journal, len(paths))
+	undos := make(map[string]*undone)
+	for _, path := range paths {
+		j, err := Load(path)
+		if err != nil {
+			continue
+		}
+		journals[path] = j
+		if j.Undoes == "" {
+			continue
+		}
+
+		source := filepath.Clean(j.Undoes)
+		u, ok := undos[source]
+		if !ok {
+			u = &undone{restored: make(map[string]bool), skipped: make(map[string]bool)}
+			undos[source] = u
+		}
+		for _, entry := range j.Applied() {
+			u.restored[entry.ItemID] = true
+		}
+		for _, itemID := range j.Skipped {
+			u.skipped[itemID] = true
+		}
+	}
+	return paths, journals, undos, nil
+}
+
+// IsFor reports whether the journal records changes to the project at
+// projectURL.
+func (j *Journal) IsFor(projectURL string) bool {
+	return strings.EqualFold(strings.TrimRight(j.Project.URL, "/"), strings.TrimRight(projectURL, "/"))
+}
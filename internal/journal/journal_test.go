@@ -0,0 +1,213 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	j := New(CommandRollover, Project{URL: "https://github.com/orgs/acme/projects/7", ID: "PVT_1"}, Field{ID: "F_iter", Name: "Sprint"}, "octocat")
+	j.Record(Entry{ItemID: "PVTI_1", FromIterationID: "it-1", ToIterationID: "it-2"})
+	j.Record(Entry{ItemID: "PVTI_2", FromIterationID: "it-1", ToIterationID: "it-2", Error: "FORBIDDEN"})
+	j.Record(Entry{ItemID: "PVTI_3", FromIterationID: "it-1", ToIterationID: "it-2", Pending: true})
+
+	first, err := j.Save("")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	second, err := j.Save("")
+	if err != nil {
+		t.Fatalf("second Save: %v", err)
+	}
+	if first == second {
+		t.Errorf("both saves wrote %s", first)
+	}
+	if dir, _ := Dir(); filepath.Dir(first) != dir {
+		t.Errorf("journal written to %s, want it in %s", first, dir)
+	}
+	if !strings.HasSuffix(first, "-rollover.json") {
+		t.Errorf("journal name %s does not end with the command", first)
+	}
+
+	loaded, err := Load(first)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Actor != "octocat" || loaded.Field.ID != "F_iter" || loaded.Project.ID != "PVT_1" {
+		t.Errorf("loaded journal = %+v", loaded)
+	}
+	if len(loaded.Entries) != 3 || loaded.Entries[0].Time.IsZero() {
+		t.Fatalf("loaded entries = %+v", loaded.Entries)
+	}
+	if applied := loaded.Applied(); len(applied) != 1 || applied[0].ItemID != "PVTI_1" {
+		t.Errorf("applied entries = %+v, want only PVTI_1", applied)
+	}
+	if undoable := loaded.Undoable(); len(undoable) != 2 || undoable[0].ItemID != "PVTI_1" || undoable[1].ItemID != "PVTI_3" {
+		t.Errorf("undoable entries = %+v, want PVTI_1 and the pending PVTI_3", undoable)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	dir := t.TempDir()
+	save := func(name, command, project, undoes string) string {
+		t.Helper()
+		j := New(command, Project{URL: project}, Field{}, "")
+		j.Undoes = undoes
+		path := filepath.Join(dir, name)
+		if _, err := j.Save(path); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		return path
+	}
+
+	acme := "https://github.com/orgs/acme/projects/7"
+	other := "https://github.com/orgs/other/projects/1"
+	oldest := save("20260101T090000Z-rollover.json", CommandRollover, acme, "")
+	undone := save("20260102T090000Z-rollover.json", CommandRollover, acme, "")
+	save("20260103T090000Z-undo.json", CommandUndo, acme, undone)
+	otherProject := save("20260104T090000Z-rollover.json", CommandRollover, other, "")
+
+	tests := []struct {
+		name    string
+		project string
+		want    string
+	}{
+		{name: "any project", want: otherProject},
+		{name: "skips undone journals", project: acme, want: oldest},
+		{name: "ignores trailing slash", project: acme + "/", want: oldest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Latest(dir, tt.project)
+			if err != nil {
+				t.Fatalf("Latest: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Latest = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Latest(dir, "https://github.com/orgs/nobody/projects/2"); err == nil {
+		t.Error("Latest found a journal for a project without any")
+	}
+}
+
+func TestStanding(t *testing.T) {
+	dir := t.TempDir()
+	acme := "https://github.com/orgs/acme/projects/7"
+	save := func(name, command, project, undoes string, entries ...Entry) string {
+		t.Helper()
+		j := New(command, Project{URL: project}, Field{}, "")
+		j.Undoes = undoes
+		for _, entry := range entries {
+			j.Record(entry)
+		}
+		path := filepath.Join(dir, name)
+		if _, err := j.Save(path); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		return path
+	}
+
+	save("20260101T090000Z-rollover.json", CommandRollover, acme, "",
+		Entry{ItemID: "PVTI_1", FromIterationID: "it-1", ToIterationID: "it-2"})
+	partly := save("20260102T090000Z-rollover.json", CommandRollover, acme, "",
+		Entry{ItemID: "PVTI_2", FromIterationID: "it-1", ToIterationID: "it-2"},
+		Entry{ItemID: "PVTI_3", FromIterationID: "it-1", ToIterationID: "it-2"},
+		Entry{ItemID: "PVTI_4", FromIterationID: "it-1", ToIterationID: "it-2"})
+	// Only PVTI_2 was moved back: moving PVTI_3 back failed
+	save("20260103T090000Z-undo.json", CommandUndo, acme, partly,
+		Entry{ItemID: "PVTI_2", FromIterationID: "it-2", ToIterationID: "it-1"},
+		Entry{ItemID: "PVTI_3", FromIterationID: "it-2", ToIterationID: "it-1", Error: "FORBIDDEN"})
+	save("20260104T090000Z-apply.json", CommandApply, acme, "")
+	save("20260105T090000Z-rollover.json", CommandRollover, "https://github.com/orgs/other/projects/1", "",
+		Entry{ItemID: "PVTI_5", FromIterationID: "it-1", ToIterationID: "it-2"})
+
+	journals, err := Standing(dir, acme)
+	if err != nil {
+		t.Fatalf("Standing: %v", err)
+	}
+	var got []string
+	for _, j := range journals {
+		var items []string
+		for _, entry := range j.Entries {
+			items = append(items, entry.ItemID)
+		}
+		got = append(got, j.Command+"["+strings.Join(items, " ")+"]")
+	}
+	if got, want := strings.Join(got, ","), "rollover[PVTI_1],rollover[PVTI_3 PVTI_4],apply[]"; got != want {
+		t.Errorf("standing journals = %s, want %s", got, want)
+	}
+}
+
+func TestLatestPartlyUndone(t *testing.T) {
+	dir := t.TempDir()
+	acme := "https://github.com/orgs/acme/projects/7"
+	save := func(name string, j *Journal) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if _, err := j.Save(path); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		return path
+	}
+
+	rollover := New(CommandRollover, Project{URL: acme}, Field{}, "")
+	rollover.Record(Entry{ItemID: "PVTI_1", FromIterationID: "it-1", ToIterationID: "it-2"})
+	rollover.Record(Entry{ItemID: "PVTI_2", FromIterationID: "it-1", ToIterationID: "it-2"})
+	rollover.Record(Entry{ItemID: "PVTI_3", FromIterationID: "it-1", ToIterationID: "it-2"})
+	rollover.Record(Entry{ItemID: "PVTI_4", FromIterationID: "it-1", ToIterationID: "it-2", Error: "FORBIDDEN"})
+	source := save("20260101T090000Z-rollover.json", rollover)
+
+	// The first undo skips PVTI_1, fails PVTI_2 and is stopped before PVTI_3
+	first := New(CommandUndo, Project{URL: acme}, Field{}, "")
+	first.Undoes = source
+	first.Skipped = []string{"PVTI_1"}
+	first.Record(Entry{ItemID: "PVTI_2", FromIterationID: "it-2", ToIterationID: "it-1", Error: "FORBIDDEN"})
+	save("20260102T090000Z-undo.json", first)
+
+	if got, err := Latest(dir, acme); err != nil || got != source {
+		t.Fatalf("Latest after a partial undo = %q, %v; want %s", got, err, source)
+	}
+
+	// The second undo restores the rest; the failed PVTI_4 was never made
+	second := New(CommandUndo, Project{URL: acme}, Field{}, "")
+	second.Undoes = source
+	second.Record(Entry{ItemID: "PVTI_2", FromIterationID: "it-2", ToIterationID: "it-1"})
+	second.Record(Entry{ItemID: "PVTI_3", FromIterationID: "it-2", ToIterationID: "it-1"})
+	save("20260103T090000Z-undo.json", second)
+
+	if got, err := Latest(dir, acme); err == nil {
+		t.Errorf("Latest after the undo was finished = %s, want none", got)
+	}
+}
+
+func TestLoadRejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	j := &Journal{Version: Version + 1, StartedAt: time.Now()}
+	if _, err := j.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load accepted a journal with an unknown version")
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a machine-readable output format.
+type Format string
+
+const (
+	// Text is the default human-readable output; it is not a structured
+	// format and Write rejects it.
+	Text Format = ""
+	JSON Format = "json"
+	YAML Format = "yaml"
+	CSV  Format = "csv"
+	// Markdown writes tables for pasting into issues, wikis and retros.
+	Markdown Format = "markdown"
+)
+
+// Table is implemented by documents that can be flattened into CSV rows.
+type Table interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// MarkdownWriter is implemented by documents with their own Markdown layout.
+// Other tables are written as a single Markdown table.
+type MarkdownWriter interface {
+	WriteMarkdown(w io.Writer) error
+}
+
+// ParseFormat validates a --output value.
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(s))); format {
+	case Text, JSON, YAML, CSV, Markdown:
+		return format, nil
+	case "md":
+		return Markdown, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %q: expected json, yaml, csv or markdown", s)
+	}
+}
+
+// Write encodes doc to w in format.
+func Write(w io.Writer, format Format, doc interface{}) error {
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	case YAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case CSV:
+		table, ok := doc.(Table)
+		if !ok {
+			return fmt.Errorf("output cannot be written as csv")
+		}
+		writer := csv.NewWriter(w)
+		if err := writer.Write(table.Header()); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(table.Rows()); err != nil {
+			return err
+		}
+		return writer.Error()
+	case Markdown:
+		if doc, ok := doc.(MarkdownWriter); ok {
+			return doc.WriteMarkdown(w)
+		}
+		table, ok := doc.(Table)
+		if !ok {
+			return fmt.Errorf("output cannot be written as markdown")
+		}
+		return WriteMarkdownTable(w, table.Header(), table.Rows())
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// WriteMarkdownTable writes header and rows as a Markdown table.
+func WriteMarkdownTable(w io.Writer, header []string, rows [][]string) error {
+	var b strings.Builder
+
+	cells := make([]string, len(header))
+	rules := make([]string, len(header))
+	for i, name := range header {
+		cells[i], rules[i] = escapeMarkdown(name), "---"
+	}
+	b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	b.WriteString("| " + strings.Join(rules, " | ") + " |\n")
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = escapeMarkdown(cell)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeMarkdown keeps cell on one line and stops its pipes from ending the
+// cell.
+func escapeMarkdown(cell string) string {
+	cell = strings.Join(strings.Fields(cell), " ")
+	return strings.ReplaceAll(cell, "|", `\|`)
+}
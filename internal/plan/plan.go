@@ -0,0 +1,109 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plan describes a rollover worked out ahead of time, so that it can
+// be reviewed before it is applied.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Version is the plan format written by this package.
+const Version = 1
+
+// Plan is the set of items a rollover would move, with the state each item
+// was in when the plan was made.
+type Plan struct {
+	Version   int         `json:"version"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Project   Project     `json:"project"`
+	Field     Field       `json:"field"`
+	From      []Iteration `json:"from"`
+	To        Iteration   `json:"to"`
+	Items     []Item      `json:"items"`
+}
+
+// Project identifies the project a plan applies to.
+type Project struct {
+	URL string `json:"url"`
+	ID  string `json:"id"`
+}
+
+// Field identifies the iteration field a plan changes.
+type Field struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Iteration identifies an iteration of the field.
+type Iteration struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Item is a project item the plan moves to the To iteration. FromIterationID
+// and UpdatedAt are the item's state when the plan was made; apply refuses to
+// move an item that no longer matches them.
+type Item struct {
+	ItemID          string    `json:"itemId"`
+	Ref             string    `json:"ref,omitempty"`
+	Title           string    `json:"title,omitempty"`
+	FromIterationID string    `json:"fromIterationId"`
+	FromIteration   string    `json:"fromIteration,omitempty"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// Save writes the plan to path.
+func (p *Plan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// Load reads the plan at path.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid plan %s: %w", path, err)
+	}
+	if p.Version != Version {
+		return nil, fmt.Errorf("plan %s has unsupported version %d", path, p.Version)
+	}
+	if p.Project.ID == "" || p.Field.ID == "" || p.To.ID == "" {
+		return nil, fmt.Errorf("invalid plan %s: missing project, field or target iteration", path)
+	}
+	return &p, nil
+}
+
+// IsFor reports whether the plan changes the project at projectURL.
+func (p *Plan) IsFor(projectURL string) bool {
+	return strings.EqualFold(strings.TrimRight(p.Project.URL, "/"), strings.TrimRight(projectURL, "/"))
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	updated := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+
+	p := &Plan{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Project:   Project{URL: "https://github.com/orgs/acme/projects/7", ID: "PVT_1"},
+		Field:     Field{ID: "F_iter", Name: "Sprint"},
+		From:      []Iteration{{ID: "it-1", Title: "Sprint 1"}},
+		To:        Iteration{ID: "it-2", Title: "Sprint 2"},
+		Items:     []Item{{ItemID: "PVTI_1", FromIterationID: "it-1", UpdatedAt: updated}},
+	}
+	if err := p.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.To.ID != "it-2" || len(loaded.Items) != 1 || !loaded.Items[0].UpdatedAt.Equal(updated) {
+		t.Errorf("loaded plan = %+v", loaded)
+	}
+	if !loaded.IsFor("https://github.com/orgs/acme/projects/7/") {
+		t.Error("plan is not for the project it was made for")
+	}
+}
+
+func TestLoadRejectsInvalidPlans(t *testing.T) {
+	tests := map[string]string{
+		"not json":        `plan`,
+		"unknown version": `{"version":2,"project":{"id":"PVT_1"},"field":{"id":"F_iter"},"to":{"id":"it-2"}}`,
+		"no target":       `{"version":1,"project":{"id":"PVT_1"},"field":{"id":"F_iter"}}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.json")
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil {
+				t.Error("Load accepted an invalid plan")
+			}
+		})
+	}
+}
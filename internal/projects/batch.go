@@ -0,0 +1,166 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+)
+
+const (
+	// DefaultBatchSize is the number of updates sent per batched mutation.
+	DefaultBatchSize = 50
+	// MaxBatchSize keeps a batched mutation well under GitHub's limits on
+	// nodes and complexity per request.
+	MaxBatchSize = 100
+
+	// inFlightTimeout bounds a mutation request, retries included. Requests
+	// are detached from cancellation so that an interrupt lets them finish.
+	inFlightTimeout = 2 * time.Minute
+)
+
+// ErrNotAttempted is reported for updates that were never sent because the
+// context was cancelled first.
+var ErrNotAttempted = errors.New("update not attempted")
+
+// ItemUpdate moves one project item to an iteration.
+type ItemUpdate struct {
+	ItemID      string
+	IterationID string
+}
+
+// ItemUpdateResult reports the outcome of one ItemUpdate; Err is nil on
+// success.
+type ItemUpdateResult struct {
+	ItemID string
+	Err    error
+}
+
+// UpdateItemIterations applies updates to the iteration field fieldID,
+// packing up to batchSize of them into each aliased mutation. It returns one
+// result per update, in order. Once ctx is cancelled no further requests are
+// started, requests in flight are allowed to complete, and the updates left
+// over are reported with ErrNotAttempted. progress, when not nil, is called
+// with the results of each batch as soon as it completes.
+func (m *Manager) UpdateItemIterations(ctx context.Context, fieldID string, updates []ItemUpdate, batchSize int, progress func([]ItemUpdateResult)) []ItemUpdateResult {
+	if batchSize < 1 {
+		batchSize = DefaultBatchSize
+	}
+	if batchSize > MaxBatchSize {
+		batchSize = MaxBatchSize
+	}
+
+	results := make([]ItemUpdateResult, 0, len(updates))
+	for start := 0; start < len(updates); start += batchSize {
+		end := start + batchSize
+		if end > len(updates) {
+			end = len(updates)
+		}
+		chunk := m.updateChunk(ctx, fieldID, updates[start:end])
+		if progress != nil {
+			progress(chunk)
+		}
+		results = append(results, chunk...)
+	}
+	return results
+}
+
+func (m *Manager) updateChunk(ctx context.Context, fieldID string, chunk []ItemUpdate) []ItemUpdateResult {
+	results := make([]ItemUpdateResult, len(chunk))
+
+	if err := ctx.Err(); err != nil {
+		for i, update := range chunk {
+			results[i] = notAttempted(update, err)
+		}
+		return results
+	}
+
+	if len(chunk) == 1 {
+		results[0] = m.updateOne(ctx, fieldID, chunk[0])
+		return results
+	}
+
+	variables := map[string]interface{}{
+		"projectId": m.projectID,
+		"fieldId":   fieldID,
+	}
+	for i, update := range chunk {
+		variables[fmt.Sprintf("item%d", i)] = update.ItemID
+		variables[fmt.Sprintf("iteration%d", i)] = update.IterationID
+	}
+
+	requestCtx, cancel := inFlight(ctx)
+	defer cancel()
+
+	response, err := github.GraphQLResponse[map[string]*struct {
+		ProjectV2Item *struct {
+			ID string
+		}
+	}](requestCtx, m.client, github.UpdateItemIterationsMutation(len(chunk)), variables)
+	if err != nil {
+		// The whole request failed, so there is no telling which updates
+		// caused it. Retry them one by one to attribute the failures.
+		log.Printf("Batched update of %d items failed, retrying individually: %v", len(chunk), err)
+		for i, update := range chunk {
+			if err := ctx.Err(); err != nil {
+				results[i] = notAttempted(update, err)
+				continue
+			}
+			results[i] = m.updateOne(ctx, fieldID, update)
+		}
+		return results
+	}
+
+	for i, update := range chunk {
+		alias := fmt.Sprintf("u%d", i)
+		results[i] = ItemUpdateResult{ItemID: update.ItemID}
+
+		if result := (*response.Data)[alias]; result != nil && result.ProjectV2Item != nil {
+			continue
+		}
+		if gqlErr := response.Errors.At(alias); gqlErr != nil {
+			// The alias is an artefact of batching, leave it out of the message
+			results[i].Err = fmt.Errorf("failed to update item iteration: %w", &github.GraphQLError{Type: gqlErr.Type, Message: gqlErr.Message})
+		} else {
+			results[i].Err = fmt.Errorf("failed to update item iteration: missing data.%s.projectV2Item", alias)
+		}
+	}
+	return results
+}
+
+func (m *Manager) updateOne(ctx context.Context, fieldID string, update ItemUpdate) ItemUpdateResult {
+	requestCtx, cancel := inFlight(ctx)
+	defer cancel()
+
+	return ItemUpdateResult{
+		ItemID: update.ItemID,
+		Err:    m.UpdateItemIteration(requestCtx, update.ItemID, fieldID, update.IterationID),
+	}
+}
+
+func notAttempted(update ItemUpdate, cause error) ItemUpdateResult {
+	return ItemUpdateResult{ItemID: update.ItemID, Err: fmt.Errorf("%w: %w", ErrNotAttempted, cause)}
+}
+
+// inFlight returns the context for a mutation request started under ctx: it
+// keeps ctx's values but not its cancellation, so the request can finish.
+func inFlight(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), inFlightTimeout)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projects
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+)
+
+// CarryoverLabelPrefix starts the labels that count carryovers, e.g.
+// "carryover:2".
+const CarryoverLabelPrefix = "carryover:"
+
+// CarryoverTracker says where the number of times an item was rolled over is
+// kept: in the project number field named Field, or in a "carryover:N" label
+// when Label is set. The zero value tracks nothing.
+type CarryoverTracker struct {
+	Field string
+	Label bool
+}
+
+// Enabled reports whether carryovers are tracked at all.
+func (t *CarryoverTracker) Enabled() bool {
+	return t != nil && (t.Field != "" || t.Label)
+}
+
+// Count returns how many times issue has been rolled over so far.
+func (t *CarryoverTracker) Count(issue *github.Issue) int {
+	if t.Field != "" {
+		count, _ := GetNumberValue(issue, t.Field)
+		return int(count)
+	}
+
+	count, _ := CarryoverLabel(issue)
+	return count
+}
+
+// CarryoverLabel returns the count held in the issue's "carryover:N" label and
+// the label itself, or 0 and "" when it has none. If there are several, the
+// highest count wins.
+func CarryoverLabel(issue *github.Issue) (int, string) {
+	count, found := 0, ""
+	for _, label := range issue.Labels.Nodes {
+		if !strings.HasPrefix(strings.ToLower(label.Name), CarryoverLabelPrefix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(label.Name[len(CarryoverLabelPrefix):]))
+		if err == nil && n > count {
+			count, found = n, label.Name
+		}
+	}
+	return count, found
+}
+
+// FormatCarryoverLabel returns the label recording count carryovers.
+func FormatCarryoverLabel(count int) string {
+	return fmt.Sprintf("%s%d", CarryoverLabelPrefix, count)
+}
+
+// CountCarryovers sets Carryovers on each of issues.
+func (t *CarryoverTracker) CountCarryovers(issues []*github.Issue) {
+	if !t.Enabled() {
+		return
+	}
+	for _, issue := range issues {
+		issue.Carryovers = t.Count(issue)
+	}
+}
@@ -15,57 +15,187 @@
 package projects
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/kriscoleman/gh-projects/internal/github"
 )
 
-func FilterIncompleteIssues(issues []*github.Issue, projectID string) []*github.Issue {
+// CompletionRule decides whether a project item counts as done.
+type CompletionRule struct {
+	// Fields are the names of the single-select fields holding an item's
+	// status, matched case-insensitively.
+	Fields []string
+	// DoneValues are the terminal status values, matched case-insensitively.
+	DoneValues []string
+	// DonePattern optionally matches further terminal status values.
+	DonePattern *regexp.Regexp
+	// ClosedIsDone treats closed issues and closed or merged pull requests as
+	// done whatever their status.
+	ClosedIsDone bool
+}
+
+// DefaultCompletionRule returns the rule used when nothing is configured: a
+// "Status" or "State" field set to Done, Completed or Closed, or a closed
+// issue.
+func DefaultCompletionRule() *CompletionRule {
+	return &CompletionRule{
+		Fields:       []string{"status", "state"},
+		DoneValues:   []string{"done", "completed", "closed"},
+		ClosedIsDone: true,
+	}
+}
+
+// IsDone reports whether issue is complete under the rule.
+func (r *CompletionRule) IsDone(issue *github.Issue) bool {
+	if r.ClosedIsDone && (issue.State == "CLOSED" || issue.State == "MERGED") {
+		return true
+	}
+	return r.StatusIsDone(issue)
+}
+
+// StatusIsDone reports whether the issue's status is one of the rule's done
+// values, whatever its state.
+func (r *CompletionRule) StatusIsDone(issue *github.Issue) bool {
+	for _, fieldValue := range r.statusValues(issue) {
+		if r.isDoneValue(fieldValue.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStatus reports whether the issue has a value in one of the rule's
+// status fields.
+func (r *CompletionRule) HasStatus(issue *github.Issue) bool {
+	return len(r.statusValues(issue)) > 0
+}
+
+func (r *CompletionRule) isDoneValue(value string) bool {
+	for _, done := range r.DoneValues {
+		if strings.EqualFold(value, done) {
+			return true
+		}
+	}
+	return r.DonePattern != nil && r.DonePattern.MatchString(value)
+}
+
+func (r *CompletionRule) isStatusField(name string) bool {
+	for _, field := range r.Fields {
+		if strings.EqualFold(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusValues returns the issue's values for the rule's status fields.
+func (r *CompletionRule) statusValues(issue *github.Issue) []github.FieldValue {
+	var values []github.FieldValue
+	for _, projectItem := range issue.ProjectItems.Nodes {
+		for _, fieldValue := range projectItem.FieldValues.Nodes {
+			if fieldValue.TypeName == "ProjectV2ItemFieldSingleSelectValue" && r.isStatusField(fieldValue.Field.Name) {
+				values = append(values, fieldValue)
+			}
+		}
+	}
+	return values
+}
+
+func FilterIncompleteIssues(issues []*github.Issue, rule *CompletionRule) []*github.Issue {
 	var incomplete []*github.Issue
-	
+
 	for _, issue := range issues {
-		if issue.State == "CLOSED" {
-			continue
+		if !rule.IsDone(issue) {
+			incomplete = append(incomplete, issue)
 		}
-		
-		isDone := false
-		
-		for _, projectItem := range issue.ProjectItems.Nodes {
-			for _, fieldValue := range projectItem.FieldValues.Nodes {
-				if fieldValue.TypeName == "ProjectV2ItemFieldSingleSelectValue" {
-					fieldName := strings.ToLower(fieldValue.Field.Name)
-					valueName := strings.ToLower(fieldValue.Title)
-					
-					if (fieldName == "status" || fieldName == "state") && 
-					   (valueName == "done" || valueName == "completed" || valueName == "closed") {
-						isDone = true
-						break
-					}
-				}
-			}
-			if isDone {
+	}
+
+	return incomplete
+}
+
+// FilterByRepository keeps the issues belonging to one of repositories, given
+// as "owner/name" or just "name".
+func FilterByRepository(issues []*github.Issue, repositories []string) []*github.Issue {
+	var matched []*github.Issue
+
+	for _, issue := range issues {
+		fullName := issue.Repository.Owner.Login + "/" + issue.Repository.Name
+		for _, repo := range repositories {
+			if strings.EqualFold(repo, fullName) || strings.EqualFold(repo, issue.Repository.Name) {
+				matched = append(matched, issue)
 				break
 			}
 		}
-		
-		if !isDone {
-			incomplete = append(incomplete, issue)
+	}
+
+	return matched
+}
+
+// itemKindNames maps the names accepted by --include-types to item kinds.
+var itemKindNames = map[string]github.ItemKind{
+	"issue": github.KindIssue,
+	"pr":    github.KindPullRequest,
+	"draft": github.KindDraftIssue,
+}
+
+// ParseItemKinds converts names such as "issue", "pr" and "draft" to item
+// kinds.
+func ParseItemKinds(names []string) ([]github.ItemKind, error) {
+	kinds := make([]github.ItemKind, 0, len(names))
+	for _, name := range names {
+		kind, ok := itemKindNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown item type %q: expected issue, pr or draft", name)
 		}
+		kinds = append(kinds, kind)
 	}
-	
-	return incomplete
+	return kinds, nil
 }
 
-func GetIssueStatus(issue *github.Issue) string {
+// KindName returns the --include-types name of kind, e.g. "pr".
+func KindName(kind github.ItemKind) string {
+	for name, k := range itemKindNames {
+		if k == kind {
+			return name
+		}
+	}
+	return strings.ToLower(string(kind))
+}
+
+// FilterByKind keeps the items whose content is one of kinds.
+func FilterByKind(issues []*github.Issue, kinds []github.ItemKind) []*github.Issue {
+	var matched []*github.Issue
+
+	for _, issue := range issues {
+		for _, kind := range kinds {
+			if issue.Kind == kind {
+				matched = append(matched, issue)
+				break
+			}
+		}
+	}
+
+	return matched
+}
+
+func GetIssueStatus(issue *github.Issue, rule *CompletionRule) string {
+	if values := rule.statusValues(issue); len(values) > 0 {
+		return values[0].Name
+	}
+	return "No Status"
+}
+
+// GetNumberValue returns the issue's value in the number field named field,
+// matched case-insensitively, and whether it has one.
+func GetNumberValue(issue *github.Issue, field string) (float64, bool) {
 	for _, projectItem := range issue.ProjectItems.Nodes {
 		for _, fieldValue := range projectItem.FieldValues.Nodes {
-			if fieldValue.TypeName == "ProjectV2ItemFieldSingleSelectValue" {
-				fieldName := strings.ToLower(fieldValue.Field.Name)
-				if fieldName == "status" || fieldName == "state" {
-					return fieldValue.Title
-				}
+			if fieldValue.TypeName == "ProjectV2ItemFieldNumberValue" && strings.EqualFold(fieldValue.Field.Name, field) {
+				return fieldValue.Number, true
 			}
 		}
 	}
-	return "No Status"
-}
\ No newline at end of file
+	return 0, false
+}
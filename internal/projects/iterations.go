@@ -0,0 +1,167 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projects
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+)
+
+// Resolve finds the iteration referred to by ref, which is one of:
+//
+//   - "previous", "current" or "next"
+//   - "@N" or "@-N", an offset from the current iteration ("@-1" is the one
+//     before it)
+//   - an iteration ID
+//   - an iteration title, matched case-insensitively
+//
+// Both active and completed iterations are considered.
+func (info *IterationInfo) Resolve(ref string) (*github.Iteration, error) {
+	ref = strings.TrimSpace(ref)
+
+	switch strings.ToLower(ref) {
+	case "":
+		return nil, fmt.Errorf("empty iteration reference")
+	case "previous":
+		if info.Previous == nil {
+			return nil, fmt.Errorf("no previous iteration found - need at least 2 iterations to perform rollover")
+		}
+		return info.Previous, nil
+	case "current":
+		if info.Current == nil {
+			return nil, fmt.Errorf("no current or future iteration found")
+		}
+		return info.Current, nil
+	case "next":
+		return info.relative(ref, 1)
+	}
+
+	if strings.HasPrefix(ref, "@") {
+		offset, err := strconv.Atoi(strings.TrimPrefix(ref, "@"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid relative iteration %q: expected @N or @-N", ref)
+		}
+		return info.relative(ref, offset)
+	}
+
+	for _, iteration := range info.Iterations {
+		if iteration.ID == ref {
+			return iteration, nil
+		}
+	}
+
+	var matches []*github.Iteration
+	for _, iteration := range info.Iterations {
+		if strings.EqualFold(iteration.Title, ref) {
+			matches = append(matches, iteration)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no iteration matches %q (known iterations: %s)", ref, strings.Join(info.titles(), ", "))
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, match := range matches {
+			ids[i] = match.ID
+		}
+		return nil, fmt.Errorf("%d iterations are titled %q; use one of the IDs %s", len(matches), ref, strings.Join(ids, ", "))
+	}
+}
+
+// ResolveStart returns the date ref refers to: either a YYYY-MM-DD date or the
+// start date of the iteration ref resolves to.
+func (info *IterationInfo) ResolveStart(ref string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", strings.TrimSpace(ref)); err == nil {
+		return date, nil
+	}
+
+	iteration, err := info.Resolve(ref)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a YYYY-MM-DD date nor a known iteration: %w", ref, err)
+	}
+	return iteration.StartDate, nil
+}
+
+// Past returns, oldest first, the iterations that have ended by now and start
+// before target and on or after since. A zero since includes every past
+// iteration.
+func (info *IterationInfo) Past(since time.Time, target *github.Iteration, now time.Time) []*github.Iteration {
+	var past []*github.Iteration
+	for _, iteration := range info.Iterations {
+		if !ended(iteration, now) || iteration.ID == target.ID || !iteration.StartDate.Before(target.StartDate) {
+			continue
+		}
+		if iteration.StartDate.Before(since) {
+			continue
+		}
+		past = append(past, iteration)
+	}
+	return past
+}
+
+// Ended returns, oldest first, the last n iterations that have ended by now,
+// or every one of them when n is 0.
+func (info *IterationInfo) Ended(n int, now time.Time) []*github.Iteration {
+	var past []*github.Iteration
+	for _, iteration := range info.Iterations {
+		if ended(iteration, now) {
+			past = append(past, iteration)
+		}
+	}
+	if n > 0 && len(past) > n {
+		past = past[len(past)-n:]
+	}
+	return past
+}
+
+// ended reports whether iteration is over by now, either because GitHub lists
+// it as completed or because its last day has passed.
+func ended(iteration *github.Iteration, now time.Time) bool {
+	return iteration.Completed || !iteration.EndDate().After(now)
+}
+
+// relative returns the iteration offset positions away from the current one.
+func (info *IterationInfo) relative(ref string, offset int) (*github.Iteration, error) {
+	if info.Current == nil {
+		return nil, fmt.Errorf("cannot resolve %q: no current or future iteration found", ref)
+	}
+
+	for i, iteration := range info.Iterations {
+		if iteration != info.Current {
+			continue
+		}
+		target := i + offset
+		if target < 0 || target >= len(info.Iterations) {
+			return nil, fmt.Errorf("cannot resolve %q: no iteration %d away from %s", ref, offset, info.Current.Title)
+		}
+		return info.Iterations[target], nil
+	}
+
+	return nil, fmt.Errorf("cannot resolve %q: current iteration %s is not in the iteration list", ref, info.Current.Title)
+}
+
+func (info *IterationInfo) titles() []string {
+	titles := make([]string, len(info.Iterations))
+	for i, iteration := range info.Iterations {
+		titles[i] = iteration.Title
+	}
+	return titles
+}
@@ -15,116 +15,239 @@
 package projects
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kriscoleman/gh-projects/internal/github"
 )
 
 type Manager struct {
-	client    *github.Client
+	client    github.GraphQLDoer
 	projectID string
+	// filterUnsupported records that GitHub rejected an items filter, so
+	// later fetches go straight to scanning.
+	filterUnsupported bool
+	// warn reports problems that leave results incomplete without failing
+	// the request.
+	warn func(message string)
+	// debug receives details of how results were fetched, if set.
+	debug func(message string)
 }
 
-func NewManager(client *github.Client, projectID string) *Manager {
+func NewManager(client github.GraphQLDoer, projectID string) *Manager {
 	return &Manager{
 		client:    client,
 		projectID: projectID,
+		warn: func(message string) {
+			log.Printf("Warning: %s", message)
+		},
 	}
 }
 
+// OnWarning sets the function that receives warnings about incomplete
+// results, such as field values that could not all be fetched. By default
+// they are logged.
+func (m *Manager) OnWarning(warn func(message string)) {
+	m.warn = warn
+}
+
+func (m *Manager) warnf(format string, args ...interface{}) {
+	m.warn(fmt.Sprintf(format, args...))
+}
+
+// OnDebug sets the function that receives details of how results were
+// fetched, such as whether items were filtered by GitHub and how many pages
+// it took. By default they are dropped.
+func (m *Manager) OnDebug(debug func(message string)) {
+	m.debug = debug
+}
+
+func (m *Manager) debugf(format string, args ...interface{}) {
+	if m.debug != nil {
+		m.debug(fmt.Sprintf(format, args...))
+	}
+}
+
+// IterationInfo describes the iterations of a project's iteration field.
+// Current and Previous are chosen from the current date and may be nil when
+// the project has no such iteration; Resolve reports that as an error.
 type IterationInfo struct {
-	Current  *github.Iteration
-	Previous *github.Iteration
-	FieldID  string
+	Current   *github.Iteration
+	Previous  *github.Iteration
+	FieldID   string
+	FieldName string
+	// Iterations holds every active and completed iteration, sorted by start
+	// date.
+	Iterations []*github.Iteration
 }
 
-func (m *Manager) GetIterations() (*IterationInfo, error) {
-	result, err := m.client.GraphQL(github.GetProjectFieldsQuery, map[string]interface{}{
-		"projectId": m.projectID,
-	})
+// AmbiguousFieldError is returned by GetIterations when no field was asked
+// for and the project has more than one iteration field.
+type AmbiguousFieldError struct {
+	Fields []github.ProjectField
+}
+
+func (e *AmbiguousFieldError) Error() string {
+	return fmt.Sprintf("project has %d iteration fields, choose one with --field: %s", len(e.Fields), describeFields(e.Fields))
+}
+
+func describeFields(fields []github.ProjectField) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = fmt.Sprintf("%q (%s)", field.Name, field.ID)
+	}
+	return strings.Join(names, ", ")
+}
+
+// GetIterationFields returns the project's iteration fields.
+func (m *Manager) GetIterationFields(ctx context.Context) ([]github.ProjectField, error) {
+	fields, err := m.GetFields(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch project fields: %w", err)
+		return nil, err
 	}
 
-	node, ok := result["data"].(map[string]interface{})["node"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid project response structure")
+	var iterationFields []github.ProjectField
+	for _, field := range fields {
+		if field.DataType == "ITERATION" {
+			iterationFields = append(iterationFields, field)
+		}
 	}
+	return iterationFields, nil
+}
 
-	fields, ok := node["fields"].(map[string]interface{})["nodes"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("no fields found in project")
+// GetNumberField returns the project's number field whose name or ID is
+// fieldRef.
+func (m *Manager) GetNumberField(ctx context.Context, fieldRef string) (*github.ProjectField, error) {
+	fields, err := m.GetFields(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	var iterationField map[string]interface{}
-	var fieldID string
+	for i := range fields {
+		if fields[i].ID != fieldRef && !strings.EqualFold(fields[i].Name, fieldRef) {
+			continue
+		}
+		if fields[i].DataType != "NUMBER" {
+			return nil, fmt.Errorf("field %q is a %s field, not a number field", fields[i].Name, strings.ToLower(fields[i].DataType))
+		}
+		return &fields[i], nil
+	}
+	return nil, fmt.Errorf("no number field %q found in project", fieldRef)
+}
 
-	for _, field := range fields {
-		f := field.(map[string]interface{})
-		if dataType, ok := f["dataType"].(string); ok && dataType == "ITERATION" {
-			iterationField = f
-			fieldID = f["id"].(string)
-			break
+// GetFields returns every field of the project.
+func (m *Manager) GetFields(ctx context.Context) ([]github.ProjectField, error) {
+	var fields []github.ProjectField
+	var cursor string
+	hasNextPage := true
+
+	for hasNextPage {
+		variables := map[string]interface{}{
+			"projectId": m.projectID,
 		}
+		if cursor != "" {
+			variables["after"] = cursor
+		}
+
+		result, err := github.GraphQLTyped[github.ProjectFieldsResponse](ctx, m.client, github.GetProjectFieldsQuery, variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch project fields: %w", err)
+		}
+
+		if result.Node == nil {
+			return nil, fmt.Errorf("invalid project response: missing data.node (is %s a ProjectV2 ID?)", m.projectID)
+		}
+		if result.Node.Fields == nil {
+			return nil, fmt.Errorf("no fields found in project: missing data.node.fields")
+		}
+
+		hasNextPage = result.Node.Fields.PageInfo.HasNextPage
+		cursor = result.Node.Fields.PageInfo.EndCursor
+		if hasNextPage && cursor == "" {
+			return nil, fmt.Errorf("invalid project response: data.node.fields.pageInfo has a next page but no endCursor")
+		}
+
+		fields = append(fields, result.Node.Fields.Nodes...)
 	}
+	return fields, nil
+}
 
-	if iterationField == nil {
+// GetIterations returns the iterations of the iteration field whose name or
+// ID is fieldRef. When fieldRef is empty the project's only iteration field
+// is used; if there are several an *AmbiguousFieldError is returned.
+func (m *Manager) GetIterations(ctx context.Context, fieldRef string) (*IterationInfo, error) {
+	fields, err := m.GetIterationFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
 		return nil, fmt.Errorf("no iteration field found in project")
 	}
 
-	config, ok := iterationField["configuration"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("iteration field has no configuration")
+	var iterationField *github.ProjectField
+	if fieldRef == "" {
+		if len(fields) > 1 {
+			return nil, &AmbiguousFieldError{Fields: fields}
+		}
+		iterationField = &fields[0]
+	} else {
+		for i := range fields {
+			if fields[i].ID == fieldRef || strings.EqualFold(fields[i].Name, fieldRef) {
+				iterationField = &fields[i]
+				break
+			}
+		}
+	}
+
+	if iterationField == nil {
+		return nil, fmt.Errorf("no iteration field %q found in project (iteration fields: %s)", fieldRef, describeFields(fields))
 	}
+	fieldID := iterationField.ID
 
-	iterations, ok := config["iterations"].([]interface{})
-	if !ok {
-		iterations = []interface{}{}
+	if iterationField.Configuration == nil {
+		return nil, fmt.Errorf("iteration field %q has no configuration: missing data.node.fields.nodes.configuration", iterationField.Name)
 	}
-	
-	completedIterations, ok := config["completedIterations"].([]interface{})
-	if !ok {
-		completedIterations = []interface{}{}
+
+	iterations := iterationField.Configuration.Iterations
+	completedIterations := iterationField.Configuration.CompletedIterations
+
+	for _, iteration := range completedIterations {
+		iteration.Completed = true
 	}
-	
+
 	// Combine all iterations
-	allIterations := append(completedIterations, iterations...)
-	
+	allIterations := append(append([]*github.Iteration{}, completedIterations...), iterations...)
+
 	if len(allIterations) == 0 {
 		return nil, fmt.Errorf("no iterations found in project")
 	}
 
 	parsedIterations := make([]*github.Iteration, 0, len(allIterations))
 	now := time.Now()
-	
+
 	log.Printf("Current time: %v", now)
 	log.Printf("Found %d active iterations from API", len(iterations))
 	log.Printf("Found %d completed iterations from API", len(completedIterations))
 	log.Printf("Total iterations: %d", len(allIterations))
 
-	for _, iter := range allIterations {
-		i := iter.(map[string]interface{})
-		startDateStr := i["startDate"].(string)
-		startDate, err := time.Parse("2006-01-02", startDateStr)
-		if err != nil {
+	for _, iteration := range allIterations {
+		if err := iteration.Invalid(); err != nil {
+			m.warnf("skipping iteration %q of %s: %v", iteration.Title, iterationField.Name, err)
 			continue
 		}
-
-		iteration := &github.Iteration{
-			ID:        i["id"].(string),
-			Title:     i["title"].(string),
-			StartDate: startDate,
-			Duration:  int(i["duration"].(float64)),
-		}
 		iteration.Field.ID = fieldID
-		iteration.Field.Name = iterationField["name"].(string)
+		iteration.Field.Name = iterationField.Name
 
 		parsedIterations = append(parsedIterations, iteration)
-		
-		endDate := iteration.StartDate.AddDate(0, 0, iteration.Duration)
+
+		endDate := iteration.EndDate()
 		log.Printf("Iteration %s: %s to %s", iteration.Title, iteration.StartDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
 	}
 
@@ -136,13 +259,13 @@ func (m *Manager) GetIterations() (*IterationInfo, error) {
 
 	// Find the most recent past iteration and the current/future iteration
 	for i, iter := range parsedIterations {
-		endDate := iter.StartDate.AddDate(0, 0, iter.Duration)
-		
-		log.Printf("Checking iteration %s: start=%v, end=%v, now=%v", 
+		endDate := iter.EndDate()
+
+		log.Printf("Checking iteration %s: start=%v, end=%v, now=%v",
 			iter.Title, iter.StartDate, endDate, now)
-		log.Printf("Start <= now: %v, End > now: %v", 
+		log.Printf("Start <= now: %v, End > now: %v",
 			!iter.StartDate.After(now), endDate.After(now))
-		
+
 		// Check if this is the current iteration (we're within its date range)
 		if !iter.StartDate.After(now) && endDate.After(now) {
 			// This is the current iteration
@@ -176,29 +299,98 @@ func (m *Manager) GetIterations() (*IterationInfo, error) {
 		}
 	}
 
-	if current == nil {
-		return nil, fmt.Errorf("no current or future iteration found")
+	if current != nil {
+		log.Printf("Selected current iteration: %s", current.Title)
 	}
-
-
-	if previous == nil {
-		return nil, fmt.Errorf("no previous iteration found - need at least 2 iterations to perform rollover")
+	if previous != nil {
+		log.Printf("Selected previous iteration: %s", previous.Title)
 	}
-	
-	log.Printf("Selected current iteration: %s", current.Title)
-	log.Printf("Selected previous iteration: %s", previous.Title)
 
 	return &IterationInfo{
-		Current:  current,
-		Previous: previous,
-		FieldID:  fieldID,
+		Current:    current,
+		Previous:   previous,
+		FieldID:    fieldID,
+		FieldName:  iterationField.Name,
+		Iterations: parsedIterations,
 	}, nil
 }
 
-func (m *Manager) GetIterationItems(iterationID string) ([]*github.Issue, error) {
+// GetIterationItems returns the project items assigned to any of the given
+// iterations. Each item records the iteration it was found in.
+//
+// GitHub is asked to filter the items by iteration where it can. When it
+// rejects the filter, every item in the project is scanned instead; other
+// failures of the filtered query are returned as they are. A filter GitHub
+// accepts is trusted, including when it finds nothing, so items it reads
+// differently from the iteration titles would be missed.
+func (m *Manager) GetIterationItems(ctx context.Context, iterations ...*github.Iteration) ([]*github.Issue, error) {
+	// Iterations are matched by field too, since an item can have a value in
+	// several iteration fields
+	wanted := make(map[string]string, len(iterations))
+	for _, iteration := range iterations {
+		wanted[iteration.ID] = iteration.Field.ID
+	}
+
+	if filter := iterationFilter(iterations); filter != "" && !m.filterUnsupported {
+		items, pages, err := m.fetchIterationItems(ctx, github.GetFilteredIterationItemsQuery, filter, wanted)
+		// Only a query GitHub does not understand is worth retrying as a
+		// scan; any other failure would just happen again
+		var rejected github.GraphQLErrors
+		switch {
+		case ctx.Err() != nil:
+			return nil, fmt.Errorf("failed to fetch iteration items: %w", ctx.Err())
+		case errors.As(err, &rejected) && rejected.Rejected():
+			m.filterUnsupported = true
+			m.debugf("Filtering items with %q failed, scanning the whole project instead: %v", filter, err)
+		case err != nil:
+			return nil, err
+		default:
+			m.debugf("Fetched %d items filtered with %q in %d page(s)", len(items), filter, pages)
+			return items, nil
+		}
+	}
+
+	items, pages, err := m.fetchIterationItems(ctx, github.GetIterationItemsQuery, "", wanted)
+	if err != nil {
+		return nil, err
+	}
+	m.debugf("Fetched %d items by scanning the whole project in %d page(s)", len(items), pages)
+	return items, nil
+}
+
+// iterationFilter returns a project filter matching items in any of the
+// iterations, e.g. sprint:"Sprint 1","Sprint 2", or "" when the iterations
+// can't be expressed as one.
+func iterationFilter(iterations []*github.Iteration) string {
+	if len(iterations) == 0 {
+		return ""
+	}
+
+	field := iterations[0].Field.Name
+	titles := make([]string, 0, len(iterations))
+	for _, iteration := range iterations {
+		if iteration.Field.Name != field || iteration.Title == "" || strings.Contains(iteration.Title, `"`) {
+			return ""
+		}
+		titles = append(titles, strconv.Quote(iteration.Title))
+	}
+	if field == "" || strings.ContainsAny(field, `":`) {
+		return ""
+	}
+
+	// Filters name fields in lower case with dashes for spaces
+	name := strings.ToLower(strings.Join(strings.Fields(field), "-"))
+	return name + ":" + strings.Join(titles, ",")
+}
+
+// fetchIterationItems pages through query, narrowed by filter when it is
+// set, and returns the items in the wanted iterations with the number of
+// pages fetched.
+func (m *Manager) fetchIterationItems(ctx context.Context, query, filter string, wanted map[string]string) ([]*github.Issue, int, error) {
 	var allItems []*github.Issue
 	var cursor string
 	hasNextPage := true
+	pages := 0
 
 	for hasNextPage {
 		variables := map[string]interface{}{
@@ -207,119 +399,207 @@ func (m *Manager) GetIterationItems(iterationID string) ([]*github.Issue, error)
 		if cursor != "" {
 			variables["after"] = cursor
 		}
+		if filter != "" {
+			variables["query"] = filter
+		}
 
-		result, err := m.client.GraphQL(github.GetIterationItemsQuery, variables)
+		result, err := github.GraphQLTyped[github.ProjectItemsResponse](ctx, m.client, query, variables)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch iteration items: %w", err)
+			return nil, pages, fmt.Errorf("failed to fetch iteration items: %w", err)
 		}
+		pages++
 
-		data := result["data"].(map[string]interface{})
-		node := data["node"].(map[string]interface{})
-		items := node["items"].(map[string]interface{})
-		
-		pageInfo := items["pageInfo"].(map[string]interface{})
-		hasNextPage = pageInfo["hasNextPage"].(bool)
-		if endCursor, ok := pageInfo["endCursor"].(string); ok {
-			cursor = endCursor
+		if result.Node == nil {
+			return nil, pages, fmt.Errorf("invalid items response: missing data.node")
+		}
+		if result.Node.Items == nil {
+			return nil, pages, fmt.Errorf("invalid items response: missing data.node.items")
+		}
+		items := result.Node.Items
+
+		hasNextPage = items.PageInfo.HasNextPage
+		cursor = items.PageInfo.EndCursor
+		if hasNextPage && cursor == "" {
+			return nil, pages, fmt.Errorf("invalid items response: data.node.items.pageInfo has a next page but no endCursor")
 		}
 
-		nodes := items["nodes"].([]interface{})
-		
-		for _, item := range nodes {
-			itemData := item.(map[string]interface{})
-			
-			
-			content, ok := itemData["content"].(map[string]interface{})
-			if !ok || content == nil {
+		for _, item := range items.Nodes {
+			issue := item.Content
+			if issue == nil {
 				log.Printf("Item has no content or content is nil")
 				continue
 			}
-			
-			// Check if this is an issue by looking for required fields
-			if _, hasID := content["id"].(string); !hasID {
+
+			// Content the query doesn't select, e.g. redacted items, decodes empty
+			switch issue.Kind {
+			case github.KindIssue, github.KindPullRequest, github.KindDraftIssue:
+			default:
+				log.Printf("Skipping item %s with unsupported content type %q", item.ID, issue.Kind)
 				continue
 			}
-			if _, hasNumber := content["number"].(float64); !hasNumber {
+			if issue.ID == "" {
 				continue
 			}
-			
 
-			issue := &github.Issue{
-				ID:     content["id"].(string),
-				Number: int(content["number"].(float64)),
-				Title:  content["title"].(string),
-				State:  content["state"].(string),
+			if item.FieldValues.PageInfo.HasNextPage {
+				err := m.fetchRemainingFieldValues(ctx, &item)
+				if item.FieldValues.PageInfo.HasNextPage {
+					m.warnf("only the first %d field values of item %s could be read, so its iteration or status may be missing: %v", len(item.FieldValues.Nodes), item.ID, err)
+				}
 			}
 
-			fieldValues := itemData["fieldValues"].(map[string]interface{})["nodes"].([]interface{})
-			itemID := itemData["id"].(string)
-			
 			hasIterationMatch := false
 			var fieldValueNodes []github.FieldValue
-			
-			for _, fv := range fieldValues {
-				fieldValue := fv.(map[string]interface{})
-				
-				if fieldValue["__typename"] == "ProjectV2ItemFieldIterationValue" {
-					if iterationId, ok := fieldValue["iterationId"].(string); ok {
-						if iterationId == iterationID {
-							hasIterationMatch = true
-						}
-					} else {
+
+			for _, fieldValue := range item.FieldValues.Nodes {
+				switch fieldValue.TypeName {
+				case "ProjectV2ItemFieldIterationValue":
+					if fieldValue.IterationID == "" {
 						log.Printf("No iterationId found in field value")
+					} else if fieldID, ok := wanted[fieldValue.IterationID]; ok && fieldValue.Field.ID == fieldID {
+						hasIterationMatch = true
+						issue.IterationID = fieldValue.IterationID
+						issue.IterationTitle = fieldValue.Title
 					}
-				}
-				
-				if fieldValue["__typename"] == "ProjectV2ItemFieldSingleSelectValue" {
-					field := fieldValue["field"].(map[string]interface{})
-					fieldValueNodes = append(fieldValueNodes, github.FieldValue{
-						TypeName: "ProjectV2ItemFieldSingleSelectValue",
-						Field: struct {
-							ID   string
-							Name string
-						}{
-							ID:   field["id"].(string),
-							Name: field["name"].(string),
-						},
-						Title: fieldValue["name"].(string),
-					})
+				case "ProjectV2ItemFieldSingleSelectValue", "ProjectV2ItemFieldNumberValue":
+					fieldValueNodes = append(fieldValueNodes, fieldValue)
 				}
 			}
-			
+
 			if hasIterationMatch {
-				issue.ProjectItems.Nodes = append(issue.ProjectItems.Nodes, struct {
-					ID         string
+				issue.ProjectItems.Nodes = make([]struct {
+					ID          string
+					UpdatedAt   time.Time
 					FieldValues struct {
 						Nodes []github.FieldValue
 					}
-				}{
-					ID: itemID,
-					FieldValues: struct {
-						Nodes []github.FieldValue
-					}{
-						Nodes: fieldValueNodes,
-					},
-				})
-				
+				}, 1)
+				issue.ProjectItems.Nodes[0].ID = item.ID
+				issue.ProjectItems.Nodes[0].UpdatedAt = item.UpdatedAt
+				issue.ProjectItems.Nodes[0].FieldValues.Nodes = fieldValueNodes
+
+				if issue.Labels.PageInfo.HasNextPage {
+					m.warnf("only the first %d labels of %s %q were read, so its carryover label may be missing", len(issue.Labels.Nodes), issue.Ref(), issue.Title)
+				}
+				if issue.Assignees.PageInfo.HasNextPage {
+					m.warnf("only the first %d assignees of %s %q were read", len(issue.Assignees.Nodes), issue.Ref(), issue.Title)
+				}
 				allItems = append(allItems, issue)
 			}
 		}
 	}
 
-	return allItems, nil
+	return allItems, pages, nil
+}
+
+// fetchRemainingFieldValues appends the field values of item that did not
+// fit on the page returned with it. item's page info is kept up to date, so
+// it still has a next page when an error stops the fetch part way.
+func (m *Manager) fetchRemainingFieldValues(ctx context.Context, item *github.ProjectItem) error {
+	pageInfo := item.FieldValues.PageInfo
+
+	for pageInfo.HasNextPage {
+		if pageInfo.EndCursor == "" {
+			return fmt.Errorf("fieldValues.pageInfo has a next page but no endCursor")
+		}
+
+		result, err := github.GraphQLTyped[github.ItemFieldValuesResponse](ctx, m.client, github.GetItemFieldValuesQuery, map[string]interface{}{
+			"itemId": item.ID,
+			"after":  pageInfo.EndCursor,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch field values: %w", err)
+		}
+		if result.Node == nil || result.Node.FieldValues == nil {
+			return fmt.Errorf("invalid field values response: missing data.node.fieldValues")
+		}
+
+		item.FieldValues.Nodes = append(item.FieldValues.Nodes, result.Node.FieldValues.Nodes...)
+		pageInfo = result.Node.FieldValues.PageInfo
+		item.FieldValues.PageInfo = pageInfo
+	}
+	return nil
+}
+
+// maxNodesPerQuery is the most IDs GitHub accepts in one nodes(ids:) lookup.
+const maxNodesPerQuery = 100
+
+// ItemIteration is the current iteration of a project item, as returned by
+// GetItemIterations. IterationID is empty when the item has no iteration.
+type ItemIteration struct {
+	IterationID string
+	UpdatedAt   time.Time
+}
+
+// GetItemIterations returns the iteration each of itemIDs is currently
+// assigned in the iteration field named fieldName, keyed by item ID. Items
+// that no longer exist are left out.
+func (m *Manager) GetItemIterations(ctx context.Context, fieldName string, itemIDs []string) (map[string]ItemIteration, error) {
+	current := make(map[string]ItemIteration, len(itemIDs))
+
+	for start := 0; start < len(itemIDs); start += maxNodesPerQuery {
+		end := start + maxNodesPerQuery
+		if end > len(itemIDs) {
+			end = len(itemIDs)
+		}
+
+		// Deleted items come back as null nodes with a tolerated NOT_FOUND
+		result, err := github.GraphQLTyped[github.ItemIterationsResponse](ctx, m.client, github.GetItemIterationsQuery, map[string]interface{}{
+			"ids":   itemIDs[start:end],
+			"field": fieldName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch item iterations: %w", err)
+		}
+
+		for _, node := range result.Nodes {
+			if node == nil || node.ID == "" {
+				continue
+			}
+			state := ItemIteration{UpdatedAt: node.UpdatedAt}
+			if node.FieldValueByName != nil {
+				state.IterationID = node.FieldValueByName.IterationID
+			}
+			current[node.ID] = state
+		}
+	}
+
+	return current, nil
 }
 
-func (m *Manager) UpdateItemIteration(itemID, fieldID, iterationID string) error {
-	_, err := m.client.GraphQL(github.UpdateItemIterationMutation, map[string]interface{}{
+func (m *Manager) UpdateItemIteration(ctx context.Context, itemID, fieldID, iterationID string) error {
+	result, err := github.GraphQLTyped[github.UpdateItemFieldResponse](ctx, m.client, github.UpdateItemIterationMutation, map[string]interface{}{
 		"projectId":   m.projectID,
 		"itemId":      itemID,
 		"fieldId":     fieldID,
 		"iterationId": iterationID,
 	})
-	
 	if err != nil {
 		return fmt.Errorf("failed to update item iteration: %w", err)
 	}
-	
+
+	if result.UpdateProjectV2ItemFieldValue == nil || result.UpdateProjectV2ItemFieldValue.ProjectV2Item == nil {
+		return fmt.Errorf("failed to update item iteration: missing data.updateProjectV2ItemFieldValue.projectV2Item")
+	}
+
+	return nil
+}
+
+// UpdateItemNumber sets the number field fieldID of an item to value.
+func (m *Manager) UpdateItemNumber(ctx context.Context, itemID, fieldID string, value float64) error {
+	result, err := github.GraphQLTyped[github.UpdateItemFieldResponse](ctx, m.client, github.UpdateItemNumberMutation, map[string]interface{}{
+		"projectId": m.projectID,
+		"itemId":    itemID,
+		"fieldId":   fieldID,
+		"number":    value,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update item number field: %w", err)
+	}
+
+	if result.UpdateProjectV2ItemFieldValue == nil || result.UpdateProjectV2ItemFieldValue.ProjectV2Item == nil {
+		return fmt.Errorf("failed to update item number field: missing data.updateProjectV2ItemFieldValue.projectV2Item")
+	}
+
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,412 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projects
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kriscoleman/gh-projects/internal/github"
+)
+
+const testProjectID = "PVT_test"
+
+// day returns a YYYY-MM-DD date offset from today by n days.
+func day(n int) string {
+	return time.Now().AddDate(0, 0, n).Format("2006-01-02")
+}
+
+func fieldsResponse() string {
+	return fmt.Sprintf(`{"data":{"node":{"fields":{"nodes":[
+		{"id":"F_status","name":"Status","dataType":"SINGLE_SELECT"},
+		{"id":"F_iter","name":"Sprint","dataType":"ITERATION","configuration":{
+			"iterations":[
+				{"id":"it-3","title":"Sprint 3","startDate":%q,"duration":14},
+				{"id":"it-4","title":"Sprint 4","startDate":%q,"duration":14}
+			],
+			"completedIterations":[
+				{"id":"it-1","title":"Sprint 1","startDate":%q,"duration":14},
+				{"id":"it-2","title":"Sprint 2","startDate":%q,"duration":14}
+			]
+		}}
+	]}}}}`, day(-7), day(7), day(-35), day(-21))
+}
+
+func TestGetIterations(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.Add(github.GetProjectFieldsQuery, map[string]interface{}{"projectId": testProjectID}, fieldsResponse())
+
+	manager := NewManager(github.NewClientWithTransport(fake), testProjectID)
+	info, err := manager.GetIterations(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetIterations: %v", err)
+	}
+
+	if info.Current.ID != "it-3" {
+		t.Errorf("current iteration = %s, want it-3", info.Current.ID)
+	}
+	if info.Previous.ID != "it-2" {
+		t.Errorf("previous iteration = %s, want it-2", info.Previous.ID)
+	}
+	if info.FieldID != "F_iter" {
+		t.Errorf("field ID = %s, want F_iter", info.FieldID)
+	}
+	if info.Current.Field.Name != "Sprint" {
+		t.Errorf("field name = %s, want Sprint", info.Current.Field.Name)
+	}
+}
+
+func TestGetIterationsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{
+			name:     "missing node",
+			response: `{"data":{"node":null}}`,
+			want:     "missing data.node",
+		},
+		{
+			name:     "missing fields",
+			response: `{"data":{"node":{}}}`,
+			want:     "missing data.node.fields",
+		},
+		{
+			name:     "no iteration field",
+			response: `{"data":{"node":{"fields":{"nodes":[{"id":"F_status","name":"Status","dataType":"SINGLE_SELECT"}]}}}}`,
+			want:     "no iteration field found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := github.NewFakeTransport()
+			fake.Add(github.GetProjectFieldsQuery, map[string]interface{}{"projectId": testProjectID}, tt.response)
+
+			_, err := NewManager(github.NewClientWithTransport(fake), testProjectID).GetIterations(context.Background(), "")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("GetIterations error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIterationsInvalidStartDate(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.Add(github.GetProjectFieldsQuery, map[string]interface{}{"projectId": testProjectID}, `{"data":{"node":{"fields":{"nodes":[
+		{"id":"F_iter","name":"Sprint","dataType":"ITERATION","configuration":{"iterations":[
+			{"id":"it-1","title":"Sprint 1","startDate":"soon","duration":7},
+			{"id":"it-2","title":"Sprint 2","startDate":"2025-03-03","duration":7}
+		]}}
+	]}}}}`)
+
+	manager := NewManager(github.NewClientWithTransport(fake), testProjectID)
+	var warnings []string
+	manager.OnWarning(func(message string) { warnings = append(warnings, message) })
+
+	info, err := manager.GetIterations(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetIterations: %v", err)
+	}
+	if len(info.Iterations) != 1 || info.Iterations[0].ID != "it-2" {
+		t.Errorf("iterations = %+v, want only Sprint 2", info.Iterations)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"Sprint 1"`) || !strings.Contains(warnings[0], `invalid startDate "soon"`) {
+		t.Errorf("warnings = %q, want one about the start date of Sprint 1", warnings)
+	}
+}
+
+// sprint2 is the iteration the item tests fetch.
+func sprint2() *github.Iteration {
+	iteration := &github.Iteration{ID: "it-2", Title: "Sprint 2"}
+	iteration.Field.ID = "F_iter"
+	iteration.Field.Name = "Sprint"
+	return iteration
+}
+
+// filterRejected is GitHub refusing an items filter, which makes
+// GetIterationItems scan the whole project.
+const filterRejected = `{"errors":[{"message":"Field 'items' doesn't accept argument 'query'"}]}`
+
+func TestGetIterationItems(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.AddAny(github.GetFilteredIterationItemsQuery, filterRejected)
+	fake.Add(github.GetIterationItemsQuery, map[string]interface{}{"projectId": testProjectID}, `{"data":{"node":{"items":{
+		"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+		"nodes":[
+			{"id":"PVTI_1","content":{"__typename":"Issue","id":"I_1","number":1,"title":"In sprint","state":"OPEN","repository":{"name":"app","owner":{"login":"acme"}}},
+			 "fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"},
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":"In Progress"}
+			 ]}},
+			{"id":"PVTI_2","content":{"__typename":"Issue","id":"I_2","number":2,"title":"Other sprint","state":"OPEN"},
+			 "fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-1","title":"Sprint 1"}
+			 ]}},
+			{"id":"PVTI_6","content":{"__typename":"Issue","id":"I_6","number":6,"title":"Other field","state":"OPEN"},
+			 "fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_train","name":"Release Train"},"iterationId":"it-2","title":"Train 2"}
+			 ]}},
+			{"id":"PVTI_3","content":null,"fieldValues":{"nodes":[]}}
+		]}}}}`)
+	fake.Add(github.GetIterationItemsQuery, map[string]interface{}{"projectId": testProjectID, "after": "c1"}, `{"data":{"node":{"items":{
+		"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+		"nodes":[
+			{"id":"PVTI_4","content":{"__typename":"Issue","id":"I_4","number":4,"title":"Second page","state":"CLOSED"},
+			 "fieldValues":{"nodes":[
+				{},
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"}
+			 ]}},
+			{"id":"PVTI_5","content":{},"fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"}
+			]}}
+		]}}}}`)
+
+	manager := NewManager(github.NewClientWithTransport(fake), testProjectID)
+	issues, err := manager.GetIterationItems(context.Background(), sprint2())
+	if err != nil {
+		t.Fatalf("GetIterationItems: %v", err)
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	if issues[0].Number != 1 || issues[1].Number != 4 {
+		t.Errorf("got issues #%d and #%d, want #1 and #4", issues[0].Number, issues[1].Number)
+	}
+	if issues[0].Repository.Owner.Login != "acme" || issues[0].Repository.Name != "app" {
+		t.Errorf("repository = %s/%s, want acme/app", issues[0].Repository.Owner.Login, issues[0].Repository.Name)
+	}
+	if got := issues[0].ProjectItems.Nodes[0].ID; got != "PVTI_1" {
+		t.Errorf("project item ID = %s, want PVTI_1", got)
+	}
+	if got := GetIssueStatus(issues[0], DefaultCompletionRule()); got != "In Progress" {
+		t.Errorf("status = %q, want In Progress", got)
+	}
+	if got := len(fake.CallsFor(github.GetIterationItemsQuery)); got != 2 {
+		t.Errorf("fetched %d pages, want 2", got)
+	}
+}
+
+func TestGetIterationItemsFiltered(t *testing.T) {
+	vars := map[string]interface{}{"projectId": testProjectID, "query": `sprint:"Sprint 2"`}
+	item := `{"id":"PVTI_1","content":{"__typename":"Issue","id":"I_1","number":1,"title":"In sprint","state":"OPEN"},
+		"fieldValues":{"nodes":[{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"}]}}`
+	page := func(nodes string) string {
+		return `{"data":{"node":{"items":{"pageInfo":{"hasNextPage":false},"nodes":[` + nodes + `]}}}}`
+	}
+
+	tests := []struct {
+		name     string
+		filtered func(fake *github.FakeTransport)
+		wantScan bool
+		// wantItems is how many items should be found, all of them #1
+		wantItems int
+		// wantDebug is the last message OnDebug should receive
+		wantDebug string
+	}{
+		{
+			name:      "filtered",
+			filtered:  func(fake *github.FakeTransport) { fake.Add(github.GetFilteredIterationItemsQuery, vars, page(item)) },
+			wantItems: 1,
+			wantDebug: `Fetched 1 items filtered with "sprint:\"Sprint 2\"" in 1 page(s)`,
+		},
+		{
+			name: "filter rejected",
+			filtered: func(fake *github.FakeTransport) {
+				fake.Add(github.GetFilteredIterationItemsQuery, vars, filterRejected)
+			},
+			wantScan:  true,
+			wantItems: 1,
+			wantDebug: "Fetched 1 items by scanning the whole project in 1 page(s)",
+		},
+		{
+			// An accepted filter is trusted even when it finds nothing
+			name:      "filter finds nothing",
+			filtered:  func(fake *github.FakeTransport) { fake.Add(github.GetFilteredIterationItemsQuery, vars, page("")) },
+			wantItems: 0,
+			wantDebug: `Fetched 0 items filtered with "sprint:\"Sprint 2\"" in 1 page(s)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := github.NewFakeTransport()
+			tt.filtered(fake)
+			fake.Add(github.GetIterationItemsQuery, map[string]interface{}{"projectId": testProjectID}, page(item))
+
+			manager := NewManager(github.NewClientWithTransport(fake), testProjectID)
+			var debug []string
+			manager.OnDebug(func(message string) { debug = append(debug, message) })
+			issues, err := manager.GetIterationItems(context.Background(), sprint2())
+			if err != nil {
+				t.Fatalf("GetIterationItems: %v", err)
+			}
+			if len(issues) != tt.wantItems || (len(issues) > 0 && issues[0].Number != 1) {
+				t.Fatalf("got %d issues, want %d", len(issues), tt.wantItems)
+			}
+			if len(debug) == 0 || debug[len(debug)-1] != tt.wantDebug {
+				t.Errorf("debug messages = %q, want the last to be %q", debug, tt.wantDebug)
+			}
+
+			scanned := len(fake.CallsFor(github.GetIterationItemsQuery)) > 0
+			if scanned != tt.wantScan {
+				t.Errorf("scanned the project = %v, want %v", scanned, tt.wantScan)
+			}
+		})
+	}
+}
+
+func TestGetIterationItemsFilterFailure(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.Add(github.GetFilteredIterationItemsQuery, map[string]interface{}{"projectId": testProjectID, "query": `sprint:"Sprint 2"`},
+		`{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`)
+
+	manager := NewManager(github.NewClientWithTransport(fake), testProjectID)
+	if _, err := manager.GetIterationItems(context.Background(), sprint2()); err == nil || !strings.Contains(err.Error(), "RATE_LIMITED") {
+		t.Fatalf("GetIterationItems error = %v, want the rate limit error", err)
+	}
+	if len(fake.CallsFor(github.GetIterationItemsQuery)) > 0 {
+		t.Error("scanned the project after a failure unrelated to the filter")
+	}
+	if manager.filterUnsupported {
+		t.Error("a failure unrelated to the filter disabled filtering")
+	}
+}
+
+func TestGetIterationItemsInvalidResponse(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.AddAny(github.GetFilteredIterationItemsQuery, filterRejected)
+	fake.Add(github.GetIterationItemsQuery, map[string]interface{}{"projectId": testProjectID}, `{"data":{"node":{}}}`)
+
+	_, err := NewManager(github.NewClientWithTransport(fake), testProjectID).GetIterationItems(context.Background(), sprint2())
+	if err == nil || !strings.Contains(err.Error(), "missing data.node.items") {
+		t.Fatalf("GetIterationItems error = %v, want missing data.node.items", err)
+	}
+}
+
+func TestGetIterationFieldsPaginates(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.Add(github.GetProjectFieldsQuery, map[string]interface{}{"projectId": testProjectID}, `{"data":{"node":{"fields":{
+		"pageInfo":{"hasNextPage":true,"endCursor":"f1"},
+		"nodes":[{"id":"F_title","name":"Title","dataType":"TITLE"}]}}}}`)
+	fake.Add(github.GetProjectFieldsQuery, map[string]interface{}{"projectId": testProjectID, "after": "f1"}, `{"data":{"node":{"fields":{
+		"pageInfo":{"hasNextPage":false},
+		"nodes":[{"id":"F_train","name":"Release Train","dataType":"ITERATION","configuration":{"iterations":[]}}]}}}}`)
+
+	fields, err := NewManager(github.NewClientWithTransport(fake), testProjectID).GetIterationFields(context.Background())
+	if err != nil {
+		t.Fatalf("GetIterationFields: %v", err)
+	}
+	if len(fields) != 1 || fields[0].ID != "F_train" {
+		t.Errorf("got fields %+v, want the iteration field from the second page", fields)
+	}
+}
+
+func TestGetIterationItemsFieldValuePages(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.Add(github.GetFilteredIterationItemsQuery, map[string]interface{}{"projectId": testProjectID, "query": `sprint:"Sprint 2"`}, `{"data":{"node":{"items":{
+		"pageInfo":{"hasNextPage":false},
+		"nodes":[
+			{"id":"PVTI_wide","content":{"__typename":"Issue","id":"I_1","number":1,"title":"Wide","state":"OPEN"},
+			 "fieldValues":{"pageInfo":{"hasNextPage":true,"endCursor":"v1"},"nodes":[
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"id":"F_status","name":"Status"},"name":"Done"}
+			 ]}},
+			{"id":"PVTI_broken","content":{"__typename":"Issue","id":"I_2","number":2,"title":"Broken","state":"OPEN"},
+			 "fieldValues":{"pageInfo":{"hasNextPage":true,"endCursor":"v1"},"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"}
+			 ]}}
+		]}}}}`)
+	fake.Add(github.GetItemFieldValuesQuery, map[string]interface{}{"itemId": "PVTI_wide", "after": "v1"}, `{"data":{"node":{"fieldValues":{
+		"pageInfo":{"hasNextPage":false},
+		"nodes":[{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"}]}}}}`)
+	fake.Add(github.GetItemFieldValuesQuery, map[string]interface{}{"itemId": "PVTI_broken", "after": "v1"}, `{"data":{"node":null}}`)
+
+	manager := NewManager(github.NewClientWithTransport(fake), testProjectID)
+	var warnings []string
+	manager.OnWarning(func(message string) { warnings = append(warnings, message) })
+
+	issues, err := manager.GetIterationItems(context.Background(), sprint2())
+	if err != nil {
+		t.Fatalf("GetIterationItems: %v", err)
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	if got := GetIssueStatus(issues[0], DefaultCompletionRule()); got != "Done" {
+		t.Errorf("status of the wide item = %q, want Done", got)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "PVTI_broken") {
+		t.Errorf("warnings = %q, want one about PVTI_broken", warnings)
+	}
+}
+
+func TestGetIterationItemsTruncatedConnections(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.Add(github.GetFilteredIterationItemsQuery, map[string]interface{}{"projectId": testProjectID, "query": `sprint:"Sprint 2"`}, `{"data":{"node":{"items":{
+		"pageInfo":{"hasNextPage":false},
+		"nodes":[
+			{"id":"PVTI_busy","content":{"__typename":"Issue","id":"I_1","number":1,"title":"Busy","state":"OPEN",
+				"labels":{"pageInfo":{"hasNextPage":true},"nodes":[{"name":"bug"}]},
+				"assignees":{"pageInfo":{"hasNextPage":true},"nodes":[{"login":"octocat"}]}},
+			 "fieldValues":{"pageInfo":{"hasNextPage":false},"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"}
+			 ]}},
+			{"id":"PVTI_quiet","content":{"__typename":"Issue","id":"I_2","number":2,"title":"Quiet","state":"OPEN",
+				"labels":{"pageInfo":{"hasNextPage":false},"nodes":[]}},
+			 "fieldValues":{"pageInfo":{"hasNextPage":false},"nodes":[
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"id":"F_iter","name":"Sprint"},"iterationId":"it-2","title":"Sprint 2"}
+			 ]}}
+		]}}}}`)
+
+	manager := NewManager(github.NewClientWithTransport(fake), testProjectID)
+	var warnings []string
+	manager.OnWarning(func(message string) { warnings = append(warnings, message) })
+
+	if _, err := manager.GetIterationItems(context.Background(), sprint2()); err != nil {
+		t.Fatalf("GetIterationItems: %v", err)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "labels of #1") || !strings.Contains(warnings[1], "assignees of #1") {
+		t.Errorf("warnings = %q, want one about the labels and one about the assignees of #1", warnings)
+	}
+}
+
+func TestGetItemIterations(t *testing.T) {
+	fake := github.NewFakeTransport()
+	fake.Add(github.GetItemIterationsQuery, map[string]interface{}{"ids": []string{"PVTI_1", "PVTI_2", "PVTI_gone"}, "field": "Sprint"}, `{"data":{"nodes":[
+		{"id":"PVTI_1","updatedAt":"2026-10-01T09:30:00Z","fieldValueByName":{"iterationId":"it-2","title":"Sprint 2"}},
+		{"id":"PVTI_2","updatedAt":"2026-10-02T09:30:00Z","fieldValueByName":null},
+		null
+	]},"errors":[{"type":"NOT_FOUND","path":["nodes",2],"message":"Could not resolve to a node with the global id of 'PVTI_gone'"}]}`)
+
+	manager := NewManager(github.NewClientWithTransport(fake), testProjectID)
+	current, err := manager.GetItemIterations(context.Background(), "Sprint", []string{"PVTI_1", "PVTI_2", "PVTI_gone"})
+	if err != nil {
+		t.Fatalf("GetItemIterations: %v", err)
+	}
+
+	want := map[string]ItemIteration{
+		"PVTI_1": {IterationID: "it-2", UpdatedAt: time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)},
+		"PVTI_2": {UpdatedAt: time.Date(2026, 10, 2, 9, 30, 0, 0, time.UTC)},
+	}
+	if fmt.Sprint(current) != fmt.Sprint(want) {
+		t.Errorf("GetItemIterations = %v, want %v", current, want)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Kris Coleman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// out receives all human-readable output. It is stdout unless a structured
+// output format claims stdout, in which case it is moved to stderr.
+var out io.Writer = os.Stdout
+
+// SetOutput redirects human-readable output to w.
+func SetOutput(w io.Writer) {
+	out = w
+}
+
+func Printf(format string, a ...interface{}) {
+	fmt.Fprintf(out, format, a...)
+}
+
+func Println(a ...interface{}) {
+	fmt.Fprintln(out, a...)
+}
+
+func Print(a ...interface{}) {
+	fmt.Fprint(out, a...)
+}
+
+// PrintTable prints rows in aligned columns under header.
+func PrintTable(header []string, rows [][]string) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
@@ -16,74 +16,251 @@ package ui
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/kriscoleman/gh-projects/internal/github"
 	"github.com/kriscoleman/gh-projects/internal/projects"
 )
 
+// Decision is the user's answer when asked whether to move an item.
+type Decision int
+
+const (
+	DecisionSkip Decision = iota
+	DecisionMove
+	// DecisionQuit means the user asked to stop, or the prompt was
+	// interrupted; nothing further should be changed.
+	DecisionQuit
+)
+
 type Prompter struct {
-	scanner *bufio.Scanner
+	in    io.Reader
+	lines chan string
+	once  sync.Once
 }
 
 func NewPrompter() *Prompter {
 	return &Prompter{
-		scanner: bufio.NewScanner(os.Stdin),
+		in: os.Stdin,
+	}
+}
+
+// readLine returns the next line of input. Input is read on its own
+// goroutine so that a cancelled context interrupts a prompt that is waiting
+// on the terminal; io.EOF is returned at the end of input.
+func (p *Prompter) readLine(ctx context.Context) (string, error) {
+	p.once.Do(func() {
+		p.lines = make(chan string)
+		go func() {
+			scanner := bufio.NewScanner(p.in)
+			for scanner.Scan() {
+				p.lines <- scanner.Text()
+			}
+			close(p.lines)
+		}()
+	})
+
+	select {
+	case line, ok := <-p.lines:
+		if !ok {
+			return "", io.EOF
+		}
+		return line, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
 	}
 }
 
-func (p *Prompter) ConfirmIssue(issue *github.Issue) bool {
-	status := projects.GetIssueStatus(issue)
-	fmt.Printf("\n📋 Issue #%d: %s\n", issue.Number, issue.Title)
-	fmt.Printf("   Status: %s\n", status)
-	fmt.Printf("   State: %s\n", issue.State)
-	fmt.Print("   Move to current iteration? (y/n/q): ")
-	
-	p.scanner.Scan()
-	response := strings.ToLower(strings.TrimSpace(p.scanner.Text()))
-	
+func (p *Prompter) ConfirmIssue(ctx context.Context, issue *github.Issue, rule *projects.CompletionRule, target string) Decision {
+	status := projects.GetIssueStatus(issue, rule)
+	Printf("\n📋 %s: %s\n", describeItem(issue), issue.Title)
+	if issue.Repository.Name != "" {
+		Printf("   Repository: %s/%s\n", issue.Repository.Owner.Login, issue.Repository.Name)
+	}
+	Printf("   Status: %s\n", status)
+	if issue.State != "" {
+		Printf("   State: %s\n", issue.State)
+	}
+	if issue.Carryovers > 0 {
+		Printf("   Carried over: %s\n", carryovers(issue.Carryovers))
+	}
+	Printf("   Move to %s? (y/n/q): ", target)
+
+	line, err := p.readLine(ctx)
+	if err != nil && err != io.EOF {
+		Println()
+		return DecisionQuit
+	}
+	response := strings.ToLower(strings.TrimSpace(line))
+
 	switch response {
 	case "y", "yes":
-		return true
+		return DecisionMove
 	case "q", "quit":
-		fmt.Println("\n❌ Operation cancelled by user")
-		os.Exit(0)
+		Println("\n❌ Operation cancelled by user")
+		return DecisionQuit
+	}
+
+	return DecisionSkip
+}
+
+// Confirm asks a yes/no question and reports whether the answer was yes. An
+// interrupted prompt or the end of input counts as no.
+func (p *Prompter) Confirm(ctx context.Context, question string) bool {
+	Printf("%s (y/n): ", question)
+
+	line, err := p.readLine(ctx)
+	if err != nil {
+		Println()
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
 	}
-	
 	return false
 }
 
-func (p *Prompter) ShowSummary(totalIssues, movedIssues int, dryRun bool) {
-	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("📊 Summary")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("Total incomplete issues found: %d\n", totalIssues)
-	
-	if dryRun {
-		fmt.Printf("Issues that would be moved: %d\n", movedIssues)
-		fmt.Println("\n🔍 This was a dry run. No changes were made.")
-	} else {
-		fmt.Printf("Issues moved to current iteration: %d\n", movedIssues)
-		fmt.Printf("Issues skipped: %d\n", totalIssues-movedIssues)
+// ChooseField asks the user to pick one of several iteration fields.
+func (p *Prompter) ChooseField(ctx context.Context, fields []github.ProjectField) (*github.ProjectField, error) {
+	Println("\n🗂️  This project has several iteration fields:")
+	for i, field := range fields {
+		Printf("   %d. %s\n", i+1, field.Name)
+	}
+
+	for {
+		Printf("   Which field should be used? (1-%d): ", len(fields))
+		line, err := p.readLine(ctx)
+		if err == io.EOF {
+			return nil, fmt.Errorf("no iteration field chosen")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("no iteration field chosen: %w", err)
+		}
+
+		choice, err := strconv.Atoi(strings.TrimSpace(line))
+		if err == nil && choice >= 1 && choice <= len(fields) {
+			return &fields[choice-1], nil
+		}
+		Println("   Please enter one of the listed numbers.")
 	}
 }
 
-func PrintIssueList(issues []*github.Issue, title string) {
-	fmt.Printf("\n%s (%d issues):\n", title, len(issues))
-	fmt.Println(strings.Repeat("-", 50))
-	
+// Summary counts what a rollover did with the incomplete items it found.
+type Summary struct {
+	// Target is the title of the iteration items were moved to.
+	Target   string
+	Found    int
+	Selected int
+	Moved    int
+	Failed   int
+	// NotAttempted counts selected items left alone after an interrupt.
+	NotAttempted int
+	DryRun       bool
+	Interrupted  bool
+}
+
+func (p *Prompter) ShowSummary(summary Summary) {
+	Println("\n" + strings.Repeat("=", 50))
+	Println("📊 Summary")
+	Println(strings.Repeat("=", 50))
+	Printf("Total incomplete issues found: %d\n", summary.Found)
+
+	if summary.DryRun {
+		Printf("Issues that would be moved: %d\n", summary.Selected)
+		Println("\n🔍 This was a dry run. No changes were made.")
+		return
+	}
+
+	Printf("Issues moved to %s: %d\n", summary.Target, summary.Moved)
+	if summary.Failed > 0 {
+		Printf("Issues that failed to move: %d\n", summary.Failed)
+	}
+	if summary.NotAttempted > 0 {
+		Printf("Issues not moved because of the interrupt: %d\n", summary.NotAttempted)
+	}
+	Printf("Issues skipped: %d\n", summary.Found-summary.Selected)
+
+	if summary.Interrupted {
+		Println("\n⚠️  The rollover was interrupted before it finished.")
+	}
+}
+
+// PrintIssueList prints issues one per line. When they come from more than
+// one iteration they are grouped under their source iteration, in the order
+// the groups first appear.
+func PrintIssueList(issues []*github.Issue, title string, rule *projects.CompletionRule) {
+	Printf("\n%s (%d issues):\n", title, len(issues))
+	Println(strings.Repeat("-", 50))
+
+	var order []string
+	groups := make(map[string][]*github.Issue)
 	for _, issue := range issues {
-		status := projects.GetIssueStatus(issue)
-		fmt.Printf("• #%d: %s [%s]\n", issue.Number, issue.Title, status)
+		if _, ok := groups[issue.IterationTitle]; !ok {
+			order = append(order, issue.IterationTitle)
+		}
+		groups[issue.IterationTitle] = append(groups[issue.IterationTitle], issue)
+	}
+
+	for _, iteration := range order {
+		indent := ""
+		if len(order) > 1 {
+			Printf("\n%s (%d):\n", iteration, len(groups[iteration]))
+			indent = "  "
+		}
+		for _, issue := range groups[iteration] {
+			status := projects.GetIssueStatus(issue, rule)
+			if issue.Carryovers > 0 {
+				Printf("%s• %s: %s [%s] (carried over %s)\n", indent, issue.Ref(), issue.Title, status, carryovers(issue.Carryovers))
+			} else {
+				Printf("%s• %s: %s [%s]\n", indent, issue.Ref(), issue.Title, status)
+			}
+		}
 	}
 }
 
-func PrintIterationInfo(info *projects.IterationInfo) {
-	fmt.Println("\n🔄 Iteration Information")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("Previous iteration: %s\n", info.Previous.Title)
-	fmt.Printf("Current iteration: %s\n", info.Current.Title)
-	fmt.Printf("Iteration field: %s\n", info.Current.Field.Name)
-}
\ No newline at end of file
+func carryovers(n int) string {
+	if n == 1 {
+		return "1 time"
+	}
+	return fmt.Sprintf("%d times", n)
+}
+
+// describeItem returns the item's kind and number, e.g. "Pull request #12".
+func describeItem(issue *github.Issue) string {
+	switch issue.Kind {
+	case github.KindPullRequest:
+		return fmt.Sprintf("Pull request #%d", issue.Number)
+	case github.KindDraftIssue:
+		return "Draft issue"
+	default:
+		return fmt.Sprintf("Issue #%d", issue.Number)
+	}
+}
+
+func PrintIterationInfo(from []*github.Iteration, to *github.Iteration) {
+	Println("\n🔄 Iteration Information")
+	Println(strings.Repeat("=", 50))
+	if len(from) == 1 {
+		Printf("From iteration: %s (%s)\n", from[0].Title, formatDates(from[0]))
+	} else {
+		Printf("From iterations (%d):\n", len(from))
+		for _, iteration := range from {
+			Printf("  • %s (%s)\n", iteration.Title, formatDates(iteration))
+		}
+	}
+	Printf("To iteration: %s (%s)\n", to.Title, formatDates(to))
+	Printf("Iteration field: %s\n", to.Field.Name)
+}
+
+func formatDates(iteration *github.Iteration) string {
+	end := iteration.EndDate().AddDate(0, 0, -1)
+	return iteration.StartDate.Format("Jan 2") + " - " + end.Format("Jan 2")
+}
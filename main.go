@@ -15,13 +15,22 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/kriscoleman/gh-projects/internal/commands"
 )
 
 func main() {
-	if err := commands.NewRootCmd().Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// After the first interrupt restore the default handling, so a second
+	// Ctrl-C exits immediately
+	context.AfterFunc(ctx, stop)
+
+	if err := commands.NewRootCmd().ExecuteContext(ctx); err != nil {
 		os.Exit(1)
 	}
 }
\ No newline at end of file